@@ -0,0 +1,175 @@
+package textn8r
+
+import (
+	"sync"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// AppendNormalize appends the normalized form of src to dst and returns the
+// extended buffer, like Apply on string(src). The built-in normalizers that
+// Compile fuses, including the Replace normalizers taking a replacement,
+// TrimSpaceNormalizer, RemoveExtraSpaceNormalizer and the normalization forms
+// do not allocate when dst has room for the result.
+// Other normalizers run on a copy of src. dst and src must not overlap.
+func (n Normalizer) AppendNormalize(dst, src []byte) []byte {
+	if d := describe(n); d != nil {
+		switch {
+		case d.appendTo != nil:
+			return d.appendTo(dst, unsafeString(src))
+		case d.fusible:
+			return appendStep(dst, d.step, unsafeString(src))
+		}
+	}
+
+	// Custom normalizers may keep their input, so they get a copy.
+	return append(dst, n(string(src))...)
+}
+
+// AppendNormalize appends the result of applying all normalizers in the
+// collection to src to dst and returns the extended buffer. Intermediate
+// results go to buffers that are reused across calls, so a pipeline of
+// normalizers that do not allocate on their own does not allocate either once
+// warmed up. dst and src must not overlap.
+func (n Normalizers) AppendNormalize(dst, src []byte) []byte {
+	if len(n) == 0 {
+		return append(dst, src...)
+	}
+
+	bufs := appendBuffers.Get().(*[2][]byte)
+	for i, normalizer := range n[:len(n)-1] {
+		bufs[i%2] = normalizer.AppendNormalize(bufs[i%2][:0], src)
+		src = bufs[i%2]
+	}
+	dst = n[len(n)-1].AppendNormalize(dst, src)
+	for i := range bufs {
+		if cap(bufs[i]) > maxAppendBuffer {
+			bufs[i] = nil
+		}
+	}
+	appendBuffers.Put(bufs)
+
+	return dst
+}
+
+// appendBuffers holds the pairs of buffers Normalizers.AppendNormalize
+// alternates between.
+var appendBuffers = sync.Pool{
+	New: func() any { return new([2][]byte) },
+}
+
+// maxAppendBuffer is the capacity above which buffers are not kept for reuse.
+const maxAppendBuffer = 1 << 20
+
+// stepPass is a fusedPass of a single step.
+type stepPass struct {
+	steps [1]runeStep
+	pass  fusedPass
+}
+
+// stepPasses holds the passes of appendStep, which would otherwise escape to
+// the heap on every call.
+var stepPasses = sync.Pool{
+	New: func() any { return new(stepPass) },
+}
+
+// appendStep appends the output of the step on input to dst.
+func appendStep(dst []byte, step runeStep, input string) []byte {
+	switch step.kind {
+	case stepReplace:
+		return appendReplaced(dst, input, step.match, step.replacement, step.runs)
+	case stepMap:
+		return appendMapped(dst, input, step.mapping)
+	}
+
+	s := stepPasses.Get().(*stepPass)
+	s.steps[0] = step
+	s.pass = fusedPass{steps: s.steps[:], input: input, appending: true, dst: dst}
+	s.pass.run()
+	dst = s.pass.dst
+	*s = stepPass{}
+	stepPasses.Put(s)
+
+	return dst
+}
+
+// appendReplaced appends the input to dst like replaceRunes would return it.
+func appendReplaced(dst []byte, input string, match func(rune) bool, replacement string, runs bool) []byte {
+	start := 0
+	matching := false
+	for i := 0; i < len(input); {
+		r, size := rune(input[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(input[i:])
+		}
+		if match(r) {
+			dst = append(dst, input[start:i]...)
+			if !runs || !matching {
+				dst = append(dst, replacement...)
+			}
+			start = i + size
+			matching = true
+		} else {
+			matching = false
+		}
+		i += size
+	}
+
+	return append(dst, input[start:]...)
+}
+
+// appendMapped appends the input mapped like strings.Map would return it to
+// dst. Invalid UTF-8 bytes are written as utf8.RuneError.
+func appendMapped(dst []byte, input string, mapping func(rune) rune) []byte {
+	for i := 0; i < len(input); {
+		r, size := rune(input[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(input[i:])
+		}
+		if m := mapping(r); m < utf8.RuneSelf {
+			dst = append(dst, byte(m))
+		} else {
+			dst = utf8.AppendRune(dst, m)
+		}
+		i += size
+	}
+
+	return dst
+}
+
+// appendFields appends the fields of the input, as split by strings.Fields,
+// joined with single spaces to dst.
+func appendFields(dst []byte, input string) []byte {
+	if !hasExtraSpace(input) {
+		return append(dst, input...)
+	}
+
+	start := len(dst)
+	space := false
+	for i := 0; i < len(input); {
+		r, size := rune(input[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(input[i:])
+		}
+		if unicode.IsSpace(r) {
+			space = len(dst) > start
+		} else {
+			if space {
+				dst = append(dst, ' ')
+				space = false
+			}
+			dst = append(dst, input[i:i+size]...)
+		}
+		i += size
+	}
+
+	return dst
+}
+
+// unsafeString returns a string sharing the bytes of b. It is only handed to
+// built-in normalizers, which do not keep their input, and b must not change
+// while the string is in use.
+func unsafeString(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
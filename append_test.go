@@ -0,0 +1,164 @@
+package textn8r
+
+import (
+	"strings"
+	"testing"
+)
+
+// appendNormalizers lists built-in normalizers beyond builtinNormalizers that
+// have a byte path.
+var appendNormalizers = []struct {
+	name       string
+	normalizer Normalizer
+}{
+	{"CaseFoldNormalizer", CaseFoldNormalizer},
+	{"NFCNormalizer", NFCNormalizer},
+	{"NFDNormalizer", NFDNormalizer},
+	{"NFKCNormalizer", NFKCNormalizer},
+	{"NFKDNormalizer", NFKDNormalizer},
+}
+
+func TestAppendNormalize(t *testing.T) {
+	normalizers := []struct {
+		name       string
+		normalizer Normalizer
+	}{
+		{"TitleCaseNormalizer", TitleCaseNormalizer(TitleCaseAP)},
+		{"custom", func(input string) string { return strings.Repeat(input, 2) }},
+	}
+	for _, tt := range builtinNormalizers {
+		normalizers = append(normalizers, struct {
+			name       string
+			normalizer Normalizer
+		}{tt.name, tt.normalizer})
+	}
+	normalizers = append(normalizers, appendNormalizers...)
+
+	inputs := []string{
+		"",
+		benchmarkInput,
+		"  leading and   trailing \t",
+		"invalid \xff\xfe bytes \xe2\x82 and �",
+		"é ẛ̣ ﬁ 가 Ω",
+		"\t",
+		"é",
+	}
+
+	for _, tt := range normalizers {
+		for _, input := range inputs {
+			expected := "prefix:" + tt.normalizer(input)
+			if result := string(tt.normalizer.AppendNormalize([]byte("prefix:"), []byte(input))); result != expected {
+				t.Errorf("%s.AppendNormalize(%+q) = %+q; want %+q", tt.name, input, result, expected)
+			}
+		}
+	}
+}
+
+func TestAppendNormalizeDoesNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops values under the race detector")
+	}
+
+	dst := make([]byte, 0, 4*len(benchmarkInput))
+	src := []byte(benchmarkInput)
+	for _, tt := range builtinNormalizers {
+		allocs := testing.AllocsPerRun(100, func() {
+			dst = tt.normalizer.AppendNormalize(dst[:0], src)
+		})
+		if allocs != 0 {
+			t.Errorf("%s.AppendNormalize allocated %v times; want 0", tt.name, allocs)
+		}
+	}
+	for _, tt := range appendNormalizers {
+		allocs := testing.AllocsPerRun(100, func() {
+			dst = tt.normalizer.AppendNormalize(dst[:0], src)
+		})
+		if allocs != 0 {
+			t.Errorf("%s.AppendNormalize allocated %v times; want 0", tt.name, allocs)
+		}
+	}
+}
+
+func TestNormalizersAppendNormalize(t *testing.T) {
+	normalizers := Normalizers{
+		NFDNormalizer,
+		ReplaceAccentsNormalizer,
+		LowerCaseNormalizer,
+		RemoveSpecialCharactersNormalizer,
+		RemoveExtraSpaceNormalizer,
+		TrimSpaceNormalizer,
+		RemoveDiacriticsNormalizer,
+	}
+
+	inputs := []string{"", benchmarkInput, "Crème Brûlée", "\xff"}
+	for _, input := range inputs {
+		expected := normalizers.Apply(input)
+		if result := string(normalizers.AppendNormalize(nil, []byte(input))); result != expected {
+			t.Errorf("AppendNormalize(%+q) = %+q; want %+q", input, result, expected)
+		}
+	}
+	if result := string(Normalizers(nil).AppendNormalize([]byte("a"), []byte("b"))); result != "ab" {
+		t.Errorf("empty Normalizers AppendNormalize = %q; want %q", result, "ab")
+	}
+
+	if raceEnabled {
+		return
+	}
+	dst := make([]byte, 0, len(benchmarkInput))
+	src := []byte(benchmarkInput)
+	dst = normalizers.AppendNormalize(dst[:0], src)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = normalizers.AppendNormalize(dst[:0], src)
+	})
+	if allocs != 0 {
+		t.Errorf("Normalizers.AppendNormalize allocated %v times; want 0", allocs)
+	}
+}
+
+func BenchmarkAppendNormalize(b *testing.B) {
+	src := []byte(benchmarkInput)
+	for _, bb := range builtinNormalizers {
+		b.Run(bb.name+"/string", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(src)))
+			for i := 0; i < b.N; i++ {
+				_ = bb.normalizer(string(src))
+			}
+		})
+		b.Run(bb.name+"/bytes", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(src)))
+			var dst []byte
+			for i := 0; i < b.N; i++ {
+				dst = bb.normalizer.AppendNormalize(dst[:0], src)
+			}
+		})
+	}
+}
+
+func BenchmarkNormalizersAppendNormalize(b *testing.B) {
+	normalizers := Normalizers{
+		TrimSpaceNormalizer,
+		RemoveExtraSpaceNormalizer,
+		ReplaceAccentsNormalizer,
+		LowerCaseNormalizer,
+		ReplaceNonAlphanumericNormalizer("-"),
+	}
+	src := []byte(benchmarkInput)
+
+	b.Run("string", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(src)))
+		for i := 0; i < b.N; i++ {
+			_ = normalizers.Apply(string(src))
+		}
+	})
+	b.Run("bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(src)))
+		var dst []byte
+		for i := 0; i < b.N; i++ {
+			dst = normalizers.AppendNormalize(dst[:0], src)
+		}
+	})
+}
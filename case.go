@@ -0,0 +1,265 @@
+package textn8r
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LocaleLithuanian keeps the dot of "i" and "j" when they carry accents.
+const LocaleLithuanian Locale = "lt"
+
+// UpperCaseLocaleNormalizer converts the input string to uppercase using the full
+// Unicode case mapping and the rules of the locale. Unlike UpperCaseNormalizer,
+// "ß" becomes "SS" and "ﬁ" becomes "FI". Turkish and Azerbaijani map "i" to "İ",
+// and Lithuanian drops the dot above kept on accented "i".
+func UpperCaseLocaleNormalizer(locale Locale) Normalizer {
+	language := locale.Language()
+	return WithScope(func(input string) string {
+		return toUpper(input, language)
+	}, ScopeStream)
+}
+
+// LowerCaseLocaleNormalizer converts the input string to lowercase using the full
+// Unicode case mapping and the rules of the locale. Unlike LowerCaseNormalizer, a
+// Greek capital sigma at the end of a word becomes the final sigma "ς". Turkish
+// and Azerbaijani map "I" to "ı" and "İ" to "i", and Lithuanian keeps the dot of
+// "i" and "j" under accents.
+func LowerCaseLocaleNormalizer(locale Locale) Normalizer {
+	language := locale.Language()
+	// Final sigmas depend on the letters after them, on the same line.
+	return WithScope(func(input string) string {
+		return toLower(input, language)
+	}, ScopeLine)
+}
+
+// CaseFoldNormalizer applies the full Unicode case folding to the input string,
+// which is meant for case-insensitive comparison and keys rather than display.
+// "Straße", "STRASSE" and "strasse" all fold to "strasse".
+func CaseFoldNormalizer(input string) string {
+	i := 0
+	for i < len(input) && input[i] < utf8.RuneSelf {
+		i++
+	}
+	if i == len(input) {
+		return strings.ToLower(input)
+	}
+
+	var b strings.Builder
+	b.Grow(len(input))
+	b.WriteString(strings.ToLower(input[:i]))
+	for i < len(input) {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		if s, ok := caseFoldTable[r]; ok {
+			b.WriteString(s)
+		} else {
+			b.WriteString(input[i : i+size])
+		}
+		i += size
+	}
+
+	return b.String()
+}
+
+func isTurkic(language Locale) bool {
+	return language == LocaleTurkish || language == LocaleAzerbaijani
+}
+
+func toUpper(input string, language Locale) string {
+	var b strings.Builder
+	b.Grow(len(input))
+
+	softDotted := false
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		raw := input[i : i+size]
+		i += size
+
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString(raw)
+			continue
+		}
+
+		// After_Soft_Dotted: the dot above an uppercase I or J is implied.
+		if language == LocaleLithuanian {
+			if r == '\u0307' && softDotted {
+				continue
+			}
+			if unicode.Is(unicode.Soft_Dotted, r) {
+				softDotted = true
+			} else if class := combiningClass(r); class == 0 || class == 230 {
+				softDotted = false
+			}
+		}
+
+		switch {
+		case isTurkic(language) && r == 'i':
+			b.WriteRune('İ')
+		case specialUpperTable[r] != "":
+			b.WriteString(specialUpperTable[r])
+		default:
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+
+	return b.String()
+}
+
+func toLower(input string, language Locale) string {
+	var b strings.Builder
+	b.Grow(len(input))
+
+	afterI := false
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		raw := input[i : i+size]
+		i += size
+
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString(raw)
+			continue
+		}
+
+		if isTurkic(language) {
+			// After_I: the dot above an I is the dot of the lowercase i.
+			if r == '\u0307' && afterI {
+				continue
+			}
+			if r == 'I' {
+				afterI = true
+			} else if class := combiningClass(r); class == 0 || class == 230 {
+				afterI = false
+			}
+
+			switch r {
+			case 'I':
+				if dotAboveFollows(input[i:]) {
+					b.WriteByte('i')
+				} else {
+					b.WriteRune('ı')
+				}
+				continue
+			case 'İ':
+				b.WriteByte('i')
+				continue
+			}
+		}
+
+		if language == LocaleLithuanian {
+			if s, ok := lithuanianLower(r, input[i:]); ok {
+				b.WriteString(s)
+				continue
+			}
+		}
+
+		switch {
+		case r == 'Σ':
+			if isFinalSigma(input[:i-size], input[i:]) {
+				b.WriteRune('ς')
+			} else {
+				b.WriteRune('σ')
+			}
+		case specialLowerTable[r] != "":
+			b.WriteString(specialLowerTable[r])
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	return b.String()
+}
+
+// lithuanianLower implements the Lithuanian rules of SpecialCasing.txt, which
+// add an explicit dot above to "i" and "j" when they carry another accent.
+func lithuanianLower(r rune, after string) (string, bool) {
+	switch r {
+	case 'Ì':
+		return "i\u0307\u0300", true
+	case 'Í':
+		return "i\u0307\u0301", true
+	case 'Ĩ':
+		return "i\u0307\u0303", true
+	case 'I', 'J', 'Į':
+		if moreAbove(after) {
+			return string(unicode.ToLower(r)) + "\u0307", true
+		}
+	}
+
+	return "", false
+}
+
+// moreAbove reports whether s starts with a combining mark of class Above
+// with no intervening starter or other mark of class Above.
+func moreAbove(s string) bool {
+	for _, r := range s {
+		switch combiningClass(r) {
+		case 230:
+			return true
+		case 0:
+			return false
+		}
+	}
+
+	return false
+}
+
+// dotAboveFollows reports whether s starts with U+0307 COMBINING DOT ABOVE
+// with no intervening starter or mark of class Above.
+func dotAboveFollows(s string) bool {
+	for _, r := range s {
+		if r == '\u0307' {
+			return true
+		}
+		if class := combiningClass(r); class == 0 || class == 230 {
+			return false
+		}
+	}
+
+	return false
+}
+
+// isFinalSigma implements the Final_Sigma condition: the sigma follows a
+// cased letter and is not followed by one, ignoring case-ignorable characters.
+func isFinalSigma(before, after string) bool {
+	preceded := false
+	for before != "" {
+		r, size := utf8.DecodeLastRuneInString(before)
+		before = before[:len(before)-size]
+		if isCaseIgnorable(r) {
+			continue
+		}
+		preceded = isCased(r)
+		break
+	}
+	if !preceded {
+		return false
+	}
+
+	for _, r := range after {
+		if isCaseIgnorable(r) {
+			continue
+		}
+		return !isCased(r)
+	}
+
+	return true
+}
+
+// isCased reports whether r has the Cased property of the Unicode Standard.
+func isCased(r rune) bool {
+	return unicode.IsUpper(r) || unicode.IsLower(r) || unicode.IsTitle(r) ||
+		unicode.In(r, unicode.Other_Lowercase, unicode.Other_Uppercase)
+}
+
+// isCaseIgnorable reports whether r has the Case_Ignorable property of the
+// Unicode Standard.
+func isCaseIgnorable(r rune) bool {
+	switch r {
+	case '\'', '.', ':', '\u00B7', '\u0387', '\u055F', '\u05F4', '\u2018', '\u2019',
+		'\u2024', '\u2027', '\uFE13', '\uFE52', '\uFE55', '\uFF07', '\uFF0E', '\uFF1A':
+		// Word_Break MidLetter, MidNumLet and Single_Quote.
+		return true
+	}
+
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Lm, unicode.Sk)
+}
@@ -0,0 +1,88 @@
+package textn8r
+
+import (
+	"testing"
+)
+
+func TestUpperCaseLocaleNormalizer(t *testing.T) {
+	tests := []struct {
+		locale   Locale
+		input    string
+		expected string
+	}{
+		{LocaleDefault, "hello world", "HELLO WORLD"},
+		{LocaleDefault, "Straße", "STRASSE"},
+		{LocaleDefault, "ﬁnance", "FINANCE"},
+		{LocaleDefault, "ŉ", "ʼN"},
+		{LocaleDefault, "istanbul", "ISTANBUL"},
+		{LocaleTurkish, "istanbul ırmak", "İSTANBUL IRMAK"},
+		{LocaleAzerbaijani, "bakı şəhəri", "BAKI ŞƏHƏRİ"},
+		{"tr-CY", "iyi", "İYİ"},
+		{LocaleLithuanian, "i\u0307\u0301", "I\u0301"},
+		{LocaleDefault, "i\u0307\u0301", "I\u0307\u0301"},
+		{LocaleDefault, "a\xffb", "A\xffB"},
+	}
+
+	for _, tt := range tests {
+		result := UpperCaseLocaleNormalizer(tt.locale).Apply(tt.input)
+		if result != tt.expected {
+			t.Errorf("UpperCaseLocaleNormalizer(%q).Apply(%+q) = %+q; want %+q", tt.locale, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestLowerCaseLocaleNormalizer(t *testing.T) {
+	tests := []struct {
+		locale   Locale
+		input    string
+		expected string
+	}{
+		{LocaleDefault, "HELLO WORLD", "hello world"},
+		{LocaleDefault, "ΟΔΥΣΣΕΥΣ", "οδυσσευς"},
+		{LocaleDefault, "ΣΑΣ ΑΣ, Σ", "σας ας, σ"},
+		{LocaleDefault, "ΑΣ. Β", "ας. β"},
+		{LocaleDefault, "ΑΣ'Β", "ασ'β"},
+		{LocaleDefault, "İ", "i\u0307"},
+		{LocaleDefault, "ISTANBUL", "istanbul"},
+		{LocaleTurkish, "ISTANBUL İZMİR", "ıstanbul izmir"},
+		{LocaleTurkish, "I\u0307", "i"},
+		{LocaleTurkish, "I\u0316\u0307", "i\u0316"},
+		{LocaleAzerbaijani, "BAKI", "bakı"},
+		{LocaleLithuanian, "I\u0301", "i\u0307\u0301"},
+		{LocaleLithuanian, "Ì", "i\u0307\u0300"},
+		{LocaleLithuanian, "I", "i"},
+		{LocaleDefault, "A\xffB", "a\xffb"},
+	}
+
+	for _, tt := range tests {
+		result := LowerCaseLocaleNormalizer(tt.locale).Apply(tt.input)
+		if result != tt.expected {
+			t.Errorf("LowerCaseLocaleNormalizer(%q).Apply(%+q) = %+q; want %+q", tt.locale, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestCaseFoldNormalizer(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Hello World", "hello world"},
+		{"Straße", "strasse"},
+		{"STRASSE", "strasse"},
+		{"ẞ", "ss"},
+		{"ΣΑΣ σας", "σασ σασ"},
+		{"ﬁ", "fi"},
+		{"İ", "i\u0307"},
+		{"Ꭰ", "Ꭰ"},
+		{"ꭰ", "Ꭰ"},
+		{"µ", "μ"},
+	}
+
+	for _, tt := range tests {
+		result := CaseFoldNormalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("CaseFoldNormalizer(%+q) = %+q; want %+q", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/p2p-b2b/textn8r"
+)
+
+// csvNormalizer applies pipelines to columns of CSV and TSV inputs.
+type csvNormalizer struct {
+	csv   *textn8r.CSVNormalizer
+	stats bool
+}
+
+// csvNormalizer builds the columns given by -column. Names made of digits
+// select columns by position.
+func (o *options) csvNormalizer() (*csvNormalizer, error) {
+	c := &textn8r.CSVNormalizer{Header: o.header, Mode: textn8r.CollectErrors}
+	if o.mode == "tsv" {
+		c.Comma = '\t'
+	}
+
+	for _, column := range o.columns {
+		name, src, _ := strings.Cut(column, "=")
+		pipeline, err := textn8r.ParsePipeline(src)
+		if err != nil {
+			return nil, fmt.Errorf("-column %s: %w", name, err)
+		}
+
+		col := textn8r.CSVColumn{Header: name, Normalizer: pipeline.NormalizerE()}
+		if name != "" && strings.Trim(name, "0123456789") == "" {
+			index, err := strconv.Atoi(name)
+			if err != nil {
+				return nil, fmt.Errorf("-column %s: %w", name, err)
+			}
+			col = textn8r.CSVColumn{Index: index, Normalizer: pipeline.NormalizerE()}
+		} else if !o.header {
+			return nil, fmt.Errorf("-column %s: columns are selected by position without header", name)
+		}
+		c.Columns = append(c.Columns, col)
+	}
+
+	return &csvNormalizer{csv: c, stats: o.stats}, nil
+}
+
+// normalize leaves the output empty when the input cannot be processed.
+func (n *csvNormalizer) normalize(name, content string) result {
+	var sb strings.Builder
+	sb.Grow(len(content))
+	stats, err := n.csv.Normalize(&sb, strings.NewReader(content))
+	res := n.result(name, stats, err)
+	if err == nil {
+		// Rejected fields are left unchanged, so the output is complete.
+		res.output = sb.String()
+	}
+
+	return res
+}
+
+func (n *csvNormalizer) stream(name string, w io.Writer, r io.Reader) result {
+	stats, err := n.csv.Normalize(w, r)
+	return n.result(name, stats, err)
+}
+
+// result reports the rejected fields and, with -stats, the counts of changed
+// fields.
+func (n *csvNormalizer) result(name string, stats *textn8r.CSVStats, err error) result {
+	if err != nil {
+		return result{errs: []error{fmt.Errorf("%s: %w", name, err)}}
+	}
+
+	var res result
+	for _, err := range stats.Errors {
+		res.errs = append(res.errs, fmt.Errorf("%s: %w", name, err))
+	}
+	if n.stats {
+		for _, column := range stats.Columns {
+			res.notes = append(res.notes, fmt.Sprintf("%s: column %q: %d of %d fields changed, %d rejected",
+				name, column.Column, column.Changed, stats.Records, column.Failed))
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCSV(t *testing.T) {
+	tests := []struct {
+		args     []string
+		stdin    string
+		expected string
+	}{
+		{
+			[]string{"-mode", "csv", "-column", "email=trim | lower", "-column", "0=upper"},
+			"name,email\r\n\"jane\", JANE@X.COM \r\n",
+			"name,email\r\n\"JANE\",jane@x.com\r\n",
+		},
+		{
+			[]string{"-mode", "tsv", "-header=false", "-column", "1=lower"},
+			"A\tB,C\n",
+			"A\tb,c\n",
+		},
+	}
+
+	for _, tt := range tests {
+		status, stdout, stderr := runTest(t, tt.stdin, tt.args...)
+		if status != 0 || stdout != tt.expected {
+			t.Errorf("run(%q) = %d, %q, %q; want 0, %q", tt.args, status, stdout, stderr, tt.expected)
+		}
+	}
+}
+
+func TestRunCSVFiles(t *testing.T) {
+	paths := writeFiles(t, "id,tag\n1,A\n2,b\n", "id,tag\n3,@\n4,x\n")
+	status, stdout, stderr := runTest(t, "",
+		"-mode", "csv", "-column", "tag=test_require_at | lower", "-stats", paths[0], paths[1])
+	if status != 1 {
+		t.Errorf("run() = %d; want 1", status)
+	}
+	if expected := "id,tag\n1,A\n2,b\nid,tag\n3,@\n4,x\n"; stdout != expected {
+		t.Errorf("stdout = %q; want %q", stdout, expected)
+	}
+	for _, expected := range []string{
+		paths[0] + `: column "tag": 0 of 2 fields changed, 2 rejected`,
+		paths[1] + `: textn8r: csv: line 3: column "tag": ` + `step "test_require_at": invalid input "x": missing "@"`,
+	} {
+		if !strings.Contains(stderr, expected) {
+			t.Errorf("stderr = %q; want it to hold %q", stderr, expected)
+		}
+	}
+
+	paths = writeFiles(t, "id,tag\n1,A\n")
+	status, _, _ = runTest(t, "", "-mode", "csv", "-column", "tag=lower", "-w", "-backup", "", paths[0])
+	if content := readFile(t, paths[0]); status != 0 || content != "id,tag\n1,a\n" {
+		t.Errorf("run(-w) = %d, %q; want 0, %q", status, content, "id,tag\n1,a\n")
+	}
+
+	status, stdout, stderr = runTest(t, "", "-mode", "csv", "-column", "mail=lower", paths[0])
+	if status != 1 || stdout != "" || !strings.Contains(stderr, `column "mail": not in the header`) {
+		t.Errorf("run() with a missing column = %d, %q, %q", status, stdout, stderr)
+	}
+}
+
+func TestRunCSVUsageErrors(t *testing.T) {
+	tests := [][]string{
+		{"-mode", "csv"},
+		{"-mode", "csv", "-column", "a=trim", "-p", "trim"},
+		{"-column", "a=trim", "-p", "trim"},
+		{"-mode", "csv", "-column", "trim"},
+	}
+	for _, args := range tests {
+		if status, _, _ := runTest(t, "", args...); status != 2 {
+			t.Errorf("run(%q) = %d; want 2", args, status)
+		}
+	}
+
+	status, _, stderr := runTest(t, "", "-mode", "csv", "-header=false", "-column", "email=trim", filepath.Join(t.TempDir(), "x.csv"))
+	if status != 1 || !strings.Contains(stderr, "by position without header") {
+		t.Errorf("run() = %d, %q; want 1 and an error", status, stderr)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around changes in diffs.
+const diffContext = 3
+
+// edit is a line of a diff: an unchanged line when kind is ' ', a removed one
+// when it is '-' and an added one when it is '+'. a and b are the positions of
+// the line in the old and new text.
+type edit struct {
+	kind byte
+	a, b int
+}
+
+// splitLines splits text after each new line, keeping them.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// maxDiffCost bounds the number of removed and added lines diffLines looks
+// for a shortest edit script with, so large rewrites stay fast.
+const maxDiffCost = 2000
+
+// diffLines returns an edit script from a to b.
+func diffLines(a, b []string) []edit {
+	n, m := len(a), len(b)
+	prefix := 0
+	for prefix < n && prefix < m && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && a[n-1-suffix] == b[m-1-suffix] {
+		suffix++
+	}
+
+	var edits []edit
+	for i := range prefix {
+		edits = append(edits, edit{' ', i, i})
+	}
+	middle, ok := shortestEdits(a[prefix:n-suffix], b[prefix:m-suffix])
+	if !ok {
+		middle = positionalEdits(a[prefix:n-suffix], b[prefix:m-suffix])
+	}
+	for _, e := range middle {
+		edits = append(edits, edit{e.kind, e.a + prefix, e.b + prefix})
+	}
+	for i := range suffix {
+		edits = append(edits, edit{' ', n - suffix + i, m - suffix + i})
+	}
+
+	return edits
+}
+
+// shortestEdits returns the shortest edit script from a to b, found with the
+// algorithm of Myers, or false when it costs more than maxDiffCost.
+func shortestEdits(a, b []string) ([]edit, bool) {
+	n, m := len(a), len(b)
+	if n+m > 0 && (n == 0 || m == 0) {
+		return positionalEdits(a, b), true
+	}
+
+	// v[offset+k] is the furthest x reached on diagonal k = x - y. trace
+	// keeps the diagonals -d-1 to d+1 of v before each round d.
+	offset := min(n+m, maxDiffCost) + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	found := n+m == 0
+	for d := 0; !found; d++ {
+		if d > maxDiffCost {
+			return nil, false
+		}
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	var edits []edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		// at returns v[offset+k] before round d.
+		at := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		var prevK int
+		if k == -d || k != d && at(k-1) < at(k+1) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{' ', x, y})
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{'+', x, prevY})
+			} else {
+				edits = append(edits, edit{'-', prevX, y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits, true
+}
+
+// positionalEdits returns an edit script changing the lines of a into the
+// lines of b at the same positions, as normalizing line by line does.
+func positionalEdits(a, b []string) []edit {
+	n, m := len(a), len(b)
+	var edits []edit
+	for i := range max(n, m) {
+		if i < n && i < m && a[i] == b[i] {
+			edits = append(edits, edit{' ', i, i})
+			continue
+		}
+		if i < n {
+			edits = append(edits, edit{'-', i, min(i, m)})
+		}
+		if i < m {
+			edits = append(edits, edit{'+', min(i+1, n), i})
+		}
+	}
+
+	return edits
+}
+
+// unifiedDiff returns the differences between two texts in the unified
+// format, or an empty string when they are equal.
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	a, b := splitLines(oldText), splitLines(newText)
+	edits := diffLines(a, b)
+
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n+++ " + newName + "\n")
+	for start := 0; start < len(edits); {
+		// Find the next change and the end of the changes that are close
+		// enough to it to share a hunk.
+		first := start
+		for first < len(edits) && edits[first].kind == ' ' {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		last := first
+		for i := first; i < len(edits); i++ {
+			if edits[i].kind != ' ' {
+				if i-last-1 > 2*diffContext {
+					break
+				}
+				last = i
+			}
+		}
+
+		from := max(first-diffContext, start)
+		to := min(last+diffContext+1, len(edits))
+		writeHunk(&sb, a, b, edits[from:to])
+		start = to
+	}
+
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, a, b []string, edits []edit) {
+	aStart, bStart := edits[0].a, edits[0].b
+	aLen, bLen := 0, 0
+	for _, e := range edits {
+		if e.kind != '+' {
+			aLen++
+		}
+		if e.kind != '-' {
+			bLen++
+		}
+	}
+
+	sb.WriteString("@@ -" + hunkRange(aStart, aLen) + " +" + hunkRange(bStart, bLen) + " @@\n")
+	for _, e := range edits {
+		var line string
+		if e.kind == '+' {
+			line = b[e.b]
+		} else {
+			line = a[e.a]
+		}
+		sb.WriteByte(e.kind)
+		sb.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange returns the range of a hunk starting at the 0-based line start.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return strconv.Itoa(start) + ",0"
+	case 1:
+		return strconv.Itoa(start + 1)
+	}
+
+	return strconv.Itoa(start+1) + "," + strconv.Itoa(length)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{
+			"change",
+			"a\nb\nc\n",
+			"a\nB\nc\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"insert into empty",
+			"",
+			"a\n",
+			"--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			"no final new line",
+			"a\nb",
+			"a\nB",
+			"--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+B\n\\ No newline at end of file\n",
+		},
+		{
+			"separate hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			"one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			"joined hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n",
+			"one\n2\n3\n4\n5\n6\n7\neight\n",
+			"--- old\n+++ new\n@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+		{
+			"removed lines",
+			"a\nb\nc\nd\n",
+			"a\nd\n",
+			"--- old\n+++ new\n@@ -1,4 +1,2 @@\n a\n-b\n-c\n d\n",
+		},
+	}
+
+	for _, tt := range tests {
+		if result := unifiedDiff("old", "new", tt.old, tt.new); result != tt.expected {
+			t.Errorf("%s: unifiedDiff() = %q; want %q", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestDiffLinesLargeRewrite(t *testing.T) {
+	// Changing every line costs more than maxDiffCost, so the lines are
+	// compared by position.
+	var a, b []string
+	for i := range 3000 {
+		line := strings.Repeat("x", i%7) + "\n"
+		a = append(a, line)
+		if i%3 == 0 {
+			line = strings.ToUpper(line) + "!"
+		}
+		b = append(b, line)
+	}
+
+	edits := diffLines(a, b)
+	var x, y int
+	for _, e := range edits {
+		switch e.kind {
+		case ' ':
+			if a[e.a] != b[e.b] || e.a != x || e.b != y {
+				t.Fatalf("bad unchanged line %+v at %d, %d", e, x, y)
+			}
+			x++
+			y++
+		case '-':
+			if e.a != x {
+				t.Fatalf("bad removed line %+v at %d", e, x)
+			}
+			x++
+		case '+':
+			if e.b != y {
+				t.Fatalf("bad added line %+v at %d", e, y)
+			}
+			y++
+		}
+	}
+	if x != len(a) || y != len(b) {
+		t.Errorf("edits end at %d, %d; want %d, %d", x, y, len(a), len(b))
+	}
+}
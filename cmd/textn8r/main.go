@@ -0,0 +1,260 @@
+// Command textn8r normalizes text from the standard input or files with a
+// pipeline of registered normalizers.
+//
+// Usage:
+//
+//	textn8r [flags] [file ...]
+//
+// The pipeline is given in the pipeline syntax with -p, such as
+//
+//	textn8r -p 'trim | lower | fold_accents' names.txt
+//
+// or read from a file with -config, in the pipeline syntax or in the JSON
+// format of textn8r.Spec. Without files, textn8r normalizes the standard
+// input. By default every line is normalized on its own; -mode file
+// normalizes each input as a whole.
+//
+// With -mode csv or -mode tsv, inputs are CSV or TSV documents and each
+// -column flag applies a pipeline to a column, selected by its header or by
+// its position from 0:
+//
+//	textn8r -mode csv -column 'email=trim | lower' -column '0=trim' users.csv
+//
+// Other fields, quotes and delimiters are copied unchanged. -stats reports
+// the number of changed fields of each column on the standard error.
+//
+// Results go to the standard output, in the order of the files. With -w,
+// files are rewritten in place, keeping the originals with the -backup
+// suffix. With -dry-run, textn8r prints a unified diff of the changes and
+// writes nothing. Files are processed concurrently, -j at a time.
+//
+// The flags are:
+//
+//	-p pipeline
+//		pipeline in the pipeline syntax
+//	-config file
+//		file holding the pipeline, in the pipeline syntax or as JSON
+//	-mode line|file|csv|tsv
+//		normalize every line, whole inputs, or columns (default line)
+//	-column name=pipeline
+//		pipeline applied to a column in csv and tsv modes; repeatable
+//	-header
+//		csv and tsv inputs start with a header record (default true)
+//	-stats
+//		report the number of changed fields of each column
+//	-w
+//		rewrite files in place
+//	-backup suffix
+//		suffix of the copies of rewritten files, or "" for none (default ".bak")
+//	-dry-run
+//		print a unified diff of the changes instead of the results
+//	-j n
+//		number of files processed at a time (default GOMAXPROCS)
+//	-list
+//		list the registered normalizers and exit
+//
+// Inputs a step rejects are left unchanged and reported on the standard
+// error; files with rejected input are not rewritten. The exit status is 1
+// when an input could not be processed and 2 on invalid flags.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/p2p-b2b/textn8r"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// options holds the parsed flags.
+type options struct {
+	pipeline string
+	config   string
+	mode     string
+	columns  []string
+	header   bool
+	stats    bool
+	inPlace  bool
+	backup   string
+	dryRun   bool
+	jobs     int
+	list     bool
+}
+
+// errUsage reports invalid flags, after printing the usage.
+var errUsage = errors.New("usage")
+
+func parseFlags(args []string, stderr io.Writer) (*options, []string, error) {
+	var o options
+	fs := flag.NewFlagSet("textn8r", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: textn8r [flags] [file ...]")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&o.pipeline, "p", "", "pipeline in the pipeline syntax")
+	fs.StringVar(&o.config, "config", "", "`file` holding the pipeline, in the pipeline syntax or as JSON")
+	fs.StringVar(&o.mode, "mode", "line", "normalize every `line`, whole inputs with file, or columns with csv and tsv")
+	fs.Func("column", "`name=pipeline` applied to a column in csv and tsv modes; repeatable", func(value string) error {
+		if !strings.Contains(value, "=") {
+			return errors.New("want name=pipeline")
+		}
+		o.columns = append(o.columns, value)
+		return nil
+	})
+	fs.BoolVar(&o.header, "header", true, "csv and tsv inputs start with a header record")
+	fs.BoolVar(&o.stats, "stats", false, "report the number of changed fields of each column")
+	fs.BoolVar(&o.inPlace, "w", false, "rewrite files in place")
+	fs.StringVar(&o.backup, "backup", ".bak", "`suffix` of the copies of rewritten files, or \"\" for none")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "print a unified diff of the changes instead of the results")
+	fs.IntVar(&o.jobs, "j", runtime.GOMAXPROCS(0), "`number` of files processed at a time")
+	fs.BoolVar(&o.list, "list", false, "list the registered normalizers and exit")
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, errUsage
+	}
+	files := fs.Args()
+
+	columnar := o.mode == "csv" || o.mode == "tsv"
+	var problem string
+	switch {
+	case o.list:
+	case o.mode != "line" && o.mode != "file" && !columnar:
+		problem = "invalid mode " + o.mode + ": want line, file, csv or tsv"
+	case columnar && len(o.columns) == 0:
+		problem = "no columns: use -column"
+	case columnar && (o.pipeline != "" || o.config != ""):
+		problem = "-p and -config do not apply to " + o.mode + " mode: use -column"
+	case !columnar && len(o.columns) > 0:
+		problem = "-column only applies to csv and tsv modes"
+	case !columnar && o.pipeline == "" && o.config == "":
+		problem = "no pipeline: use -p or -config"
+	case o.pipeline != "" && o.config != "":
+		problem = "-p and -config are exclusive"
+	case o.inPlace && len(files) == 0:
+		problem = "-w needs files"
+	case o.jobs < 1:
+		problem = "-j must be at least 1"
+	}
+	if problem != "" {
+		fmt.Fprintln(stderr, "textn8r: "+problem)
+		fs.Usage()
+		return nil, nil, errUsage
+	}
+
+	return &o, files, nil
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	o, files, err := parseFlags(args, stderr)
+	if err != nil {
+		return 2
+	}
+	if o.list {
+		return list(stdout)
+	}
+
+	n, err := o.normalizer()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if len(files) == 0 {
+		return processStdin(o, n, stdin, stdout, stderr)
+	}
+
+	return processFiles(o, n, files, stdout, stderr)
+}
+
+// normalizer returns the normalizer of the mode.
+func (o *options) normalizer() (normalizer, error) {
+	if o.mode == "csv" || o.mode == "tsv" {
+		return o.csvNormalizer()
+	}
+
+	pipeline, err := o.loadPipeline()
+	if err != nil {
+		return nil, err
+	}
+
+	return &textNormalizer{pipeline: pipeline, lines: o.mode == "line"}, nil
+}
+
+// loadPipeline builds the pipeline given by -p or -config.
+func (o *options) loadPipeline() (*textn8r.Pipeline, error) {
+	if o.pipeline != "" {
+		return textn8r.ParsePipeline(o.pipeline)
+	}
+
+	data, err := os.ReadFile(o.config)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec textn8r.Spec
+	if src := strings.TrimSpace(string(data)); strings.HasPrefix(src, "{") || strings.HasPrefix(src, "[") {
+		err = json.Unmarshal(data, &spec)
+	} else {
+		spec, err = textn8r.ParseSpec(src)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", o.config, err)
+	}
+
+	return spec.Pipeline()
+}
+
+// list prints the registered normalizers.
+func list(stdout io.Writer) int {
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	for _, d := range textn8r.List() {
+		params := make([]string, len(d.Params))
+		for i, p := range d.Params {
+			params[i] = p.Name
+		}
+		name := d.Name
+		if len(params) > 0 {
+			name += "(" + strings.Join(params, ", ") + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, d.Category, d.Description)
+	}
+	if err := w.Flush(); err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+// processStdin normalizes the standard input as it is read, or prints the
+// diff of the changes in dry-run mode.
+func processStdin(o *options, n normalizer, stdin io.Reader, stdout, stderr io.Writer) int {
+	const name = "<stdin>"
+
+	var r result
+	if o.dryRun {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		r = n.normalize(name, string(data))
+		r.output = unifiedDiff(name, name, string(data), r.output)
+		if _, err := io.WriteString(stdout, r.output); err != nil {
+			r.errs = append(r.errs, err)
+		}
+	} else {
+		r = n.stream(name, stdout, stdin)
+	}
+
+	return r.report(stderr)
+}
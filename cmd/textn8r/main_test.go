@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/p2p-b2b/textn8r"
+)
+
+func init() {
+	err := textn8r.Register(textn8r.Definition{
+		Name:     "test_require_at",
+		Category: textn8r.CategoryValidation,
+		NewE: func(textn8r.Args) (textn8r.NormalizerE, error) {
+			return func(input string) (string, error) {
+				if !strings.Contains(input, "@") {
+					return input, &textn8r.InvalidInputError{Input: input, Reason: `missing "@"`}
+				}
+				return input, nil
+			}, nil
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// runTest runs the command and returns its exit status and outputs.
+func runTest(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+// writeFiles writes the files into a temporary directory and returns their
+// paths.
+func writeFiles(t *testing.T, contents ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, len(contents))
+	for i, content := range contents {
+		paths[i] = filepath.Join(dir, "file"+string(rune('a'+i))+".txt")
+		if err := os.WriteFile(paths[i], []byte(content), 0o640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return paths
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestRunStdin(t *testing.T) {
+	tests := []struct {
+		args     []string
+		stdin    string
+		expected string
+	}{
+		{[]string{"-p", "trim | upper"}, "  a  \r\n b\n c", "A\r\nB\nC"},
+		{[]string{"-p", "remove_new_line", "-mode", "file"}, "a\nb\n", "ab"},
+		{[]string{"-p", "remove_new_line"}, "a\nb\n", "a\nb\n"},
+		{[]string{"-p", "lower", "-dry-run"}, "a\nB\nc\n", "--- <stdin>\n+++ <stdin>\n@@ -1,3 +1,3 @@\n a\n-B\n+b\n c\n"},
+		{[]string{"-p", ""}, "", ""},
+	}
+
+	for _, tt := range tests {
+		status, stdout, stderr := runTest(t, tt.stdin, tt.args...)
+		if tt.args[1] == "" {
+			if status != 2 || !strings.Contains(stderr, "no pipeline") {
+				t.Errorf("run(%q) = %d, %q; want 2 and a usage error", tt.args, status, stderr)
+			}
+			continue
+		}
+		if status != 0 || stdout != tt.expected {
+			t.Errorf("run(%q) = %d, %q, %q; want 0, %q", tt.args, status, stdout, stderr, tt.expected)
+		}
+	}
+}
+
+func TestRunRejectedLines(t *testing.T) {
+	status, stdout, stderr := runTest(t, "A@B\nab\n", "-p", "test_require_at | lower")
+	if status != 1 || stdout != "a@b\nab\n" {
+		t.Errorf("run() = %d, %q; want 1, %q", status, stdout, "a@b\nab\n")
+	}
+	expected := `<stdin>:2: textn8r: step "test_require_at": invalid input "ab": missing "@"` + "\n"
+	if stderr != expected {
+		t.Errorf("stderr = %q; want %q", stderr, expected)
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	paths := writeFiles(t, "Crème\n", "Brûlée\n", "Flan\n")
+	status, stdout, stderr := runTest(t, "", append([]string{"-p", "fold_accents | upper", "-j", "2"}, paths...)...)
+	if status != 0 || stdout != "CREME\nBRULEE\nFLAN\n" {
+		t.Errorf("run() = %d, %q, %q; want 0, %q", status, stdout, stderr, "CREME\nBRULEE\nFLAN\n")
+	}
+
+	status, _, stderr = runTest(t, "", "-p", "trim", filepath.Join(t.TempDir(), "missing.txt"))
+	if status != 1 || !strings.Contains(stderr, "missing.txt") {
+		t.Errorf("run() of a missing file = %d, %q; want 1 and an error", status, stderr)
+	}
+}
+
+func TestRunInPlace(t *testing.T) {
+	paths := writeFiles(t, " a \n b \n", "c\n")
+	status, stdout, stderr := runTest(t, "", "-p", "trim", "-w", paths[0], paths[1])
+	if status != 0 || stdout != "" || stderr != "" {
+		t.Fatalf("run() = %d, %q, %q; want 0 and no output", status, stdout, stderr)
+	}
+
+	if content := readFile(t, paths[0]); content != "a\nb\n" {
+		t.Errorf("rewritten file = %q; want %q", content, "a\nb\n")
+	}
+	if backup := readFile(t, paths[0]+".bak"); backup != " a \n b \n" {
+		t.Errorf("backup = %q; want %q", backup, " a \n b \n")
+	}
+	if info, err := os.Stat(paths[0]); err != nil || info.Mode().Perm() != 0o640 {
+		t.Errorf("rewritten file mode = %v, %v; want %v", info.Mode().Perm(), err, os.FileMode(0o640))
+	}
+	if _, err := os.Stat(paths[1] + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("unchanged file was backed up")
+	}
+
+	paths = writeFiles(t, "X\n")
+	runTest(t, "", "-p", "lower", "-w", "-backup", "", paths[0])
+	if content := readFile(t, paths[0]); content != "x\n" {
+		t.Errorf("rewritten file = %q; want %q", content, "x\n")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(paths[0]))
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files; want 1 without backup", len(entries))
+	}
+}
+
+func TestRunInPlaceRejected(t *testing.T) {
+	paths := writeFiles(t, "A@B\nab\n")
+	status, _, _ := runTest(t, "", "-p", "test_require_at | lower", "-w", paths[0])
+	if status != 1 {
+		t.Errorf("run() = %d; want 1", status)
+	}
+	if content := readFile(t, paths[0]); content != "A@B\nab\n" {
+		t.Errorf("file with rejected lines was rewritten to %q", content)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	paths := writeFiles(t, "a\nB\n", "c\n")
+	status, stdout, _ := runTest(t, "", "-p", "lower", "-w", "-dry-run", paths[0], paths[1])
+	expected := "--- " + paths[0] + "\n+++ " + paths[0] + "\n@@ -1,2 +1,2 @@\n a\n-B\n+b\n"
+	if status != 0 || stdout != expected {
+		t.Errorf("run() = %d, %q; want 0, %q", status, stdout, expected)
+	}
+	if content := readFile(t, paths[0]); content != "a\nB\n" {
+		t.Errorf("dry run rewrote the file to %q", content)
+	}
+}
+
+func TestRunConfig(t *testing.T) {
+	dir := t.TempDir()
+	configs := map[string]string{
+		"pipeline.json": `{"version": 1, "steps": [{"name": "trim"}, {"name": "replace_space", "args": {"replacement": "_"}}]}`,
+		"pipeline.txt":  "trim # leading and trailing space\n| replace_space(\"_\")\n",
+	}
+	for name, config := range configs {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		status, stdout, stderr := runTest(t, " a b \n", "-config", path)
+		if status != 0 || stdout != "a_b\n" {
+			t.Errorf("run(-config %s) = %d, %q, %q; want 0, %q", name, status, stdout, stderr, "a_b\n")
+		}
+	}
+
+	path := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "lowr"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	status, _, stderr := runTest(t, "", "-config", path)
+	if status != 1 || !strings.Contains(stderr, `steps[0].name: unknown normalizer "lowr"`) {
+		t.Errorf("run(-config bad.json) = %d, %q; want 1 and an unknown normalizer error", status, stderr)
+	}
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	tests := [][]string{
+		{"-p", "trim", "-config", "x.json"},
+		{"-p", "trim", "-mode", "word"},
+		{"-p", "trim", "-w"},
+		{"-p", "trim", "-j", "0"},
+		{"-unknown"},
+	}
+
+	for _, args := range tests {
+		if status, _, _ := runTest(t, "", args...); status != 2 {
+			t.Errorf("run(%q) = %d; want 2", args, status)
+		}
+	}
+
+	status, _, stderr := runTest(t, "", "-p", "trim | lowr")
+	if status != 1 || !strings.Contains(stderr, "line 1, column 8") {
+		t.Errorf("run() of an invalid pipeline = %d, %q; want 1 and a syntax error", status, stderr)
+	}
+}
+
+func TestRunList(t *testing.T) {
+	status, stdout, _ := runTest(t, "", "-list")
+	if status != 0 || !strings.Contains(stdout, "replace_tab(replacement)") {
+		t.Errorf("run(-list) = %d, %q", status, stdout)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/p2p-b2b/textn8r"
+)
+
+// normalizer normalizes inputs in one of the modes.
+type normalizer interface {
+	// normalize returns the normalized content of the named input.
+	normalize(name, content string) result
+	// stream normalizes the named input read from r and writes it to w.
+	stream(name string, w io.Writer, r io.Reader) result
+}
+
+// result is the outcome of processing an input.
+type result struct {
+	// output is written to the standard output: the normalized content, or
+	// the diff in dry-run mode.
+	output string
+	// notes are reported on the standard error.
+	notes []string
+	errs  []error
+}
+
+// report prints the notes and errors and returns the exit status.
+func (r result) report(stderr io.Writer) int {
+	for _, note := range r.notes {
+		fmt.Fprintln(stderr, note)
+	}
+	for _, err := range r.errs {
+		fmt.Fprintln(stderr, err)
+	}
+	if len(r.errs) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// textNormalizer applies a pipeline to inputs, line by line or as a whole.
+type textNormalizer struct {
+	pipeline *textn8r.Pipeline
+	lines    bool
+}
+
+// normalizeLine normalizes a line without its terminator, leaving the line
+// unchanged when a step rejects it.
+func (n *textNormalizer) normalizeLine(line string) (string, error) {
+	text := strings.TrimSuffix(line, "\n")
+	text = strings.TrimSuffix(text, "\r")
+	output, err := n.pipeline.Apply(text)
+	if err != nil {
+		return line, err
+	}
+
+	return output + line[len(text):], nil
+}
+
+// normalize prefixes errors with the name, and the line in line mode.
+func (n *textNormalizer) normalize(name, content string) result {
+	if !n.lines {
+		output, err := n.pipeline.Apply(content)
+		if err != nil {
+			return result{output: content, errs: []error{fmt.Errorf("%s: %w", name, err)}}
+		}
+		return result{output: output}
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(content))
+	var errs []error
+	for i, line := range splitLines(content) {
+		output, err := n.normalizeLine(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", name, i+1, err))
+		}
+		sb.WriteString(output)
+	}
+
+	return result{output: sb.String(), errs: errs}
+}
+
+// stream normalizes lines as they are read in line mode.
+func (n *textNormalizer) stream(name string, w io.Writer, r io.Reader) result {
+	if !n.lines {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return result{errs: []error{err}}
+		}
+		res := n.normalize(name, string(data))
+		if _, err := io.WriteString(w, res.output); err != nil {
+			res.errs = append(res.errs, err)
+		}
+		return result{errs: res.errs}
+	}
+
+	var res result
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for number := 1; ; number++ {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			output, lineErr := n.normalizeLine(line)
+			if lineErr != nil {
+				res.errs = append(res.errs, fmt.Errorf("%s:%d: %w", name, number, lineErr))
+			}
+			bw.WriteString(output)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			res.errs = append(res.errs, err)
+			break
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		res.errs = append(res.errs, err)
+	}
+
+	return res
+}
+
+// processFiles processes the files o.jobs at a time and writes their outputs
+// in the order of the files.
+func processFiles(o *options, n normalizer, files []string, stdout, stderr io.Writer) int {
+	results := make([]result, len(files))
+	done := make([]chan struct{}, len(files))
+	for i := range files {
+		done[i] = make(chan struct{})
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(o.jobs, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = processFile(o, n, files[i])
+				close(done[i])
+			}
+		}()
+	}
+	go func() {
+		for i := range files {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	status := 0
+	for i := range files {
+		<-done[i]
+		if _, err := io.WriteString(stdout, results[i].output); err != nil {
+			results[i].errs = append(results[i].errs, err)
+		}
+		status = max(status, results[i].report(stderr))
+		results[i] = result{}
+	}
+	wg.Wait()
+
+	return status
+}
+
+func processFile(o *options, n normalizer, path string) result {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return result{errs: []error{err}}
+	}
+	content := string(data)
+	res := n.normalize(path, content)
+
+	switch {
+	case o.dryRun:
+		res.output = unifiedDiff(path, path, content, res.output)
+		return res
+	case !o.inPlace:
+		return res
+	case len(res.errs) > 0 || res.output == content:
+		res.output = ""
+		return res
+	}
+
+	if err := rewrite(path, res.output, o.backup); err != nil {
+		res.errs = append(res.errs, err)
+	}
+	res.output = ""
+
+	return res
+}
+
+// rewrite replaces the content of the file, keeping the original with the
+// backup suffix unless it is empty. The file is replaced at once by renaming
+// a new file over it.
+func rewrite(path, content, backup string) (err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if backup != "" {
+		if err := os.Rename(path, path+backup); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			os.Rename(path+backup, path)
+			return err
+		}
+		return nil
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,298 @@
+package textn8r
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Compile returns a Normalizer that gives the same result as normalizers.Apply
+// but runs consecutive built-in normalizers that work one character at a time,
+// such as case conversion, accent folding and character removal or
+// replacement, in a single pass over the string. Other normalizers, including
+// custom ones, run in order between the fused passes.
+func Compile(normalizers Normalizers) Normalizer {
+	var segments Normalizers
+
+	var steps []runeStep
+	var pending Normalizers
+	flush := func() {
+		switch {
+		case len(steps) == 1:
+			// A single step is faster on its own.
+			segments = append(segments, pending[0])
+		case len(steps) > 1:
+			segments = append(segments, fuse(steps, pending))
+		}
+		steps, pending = nil, nil
+	}
+	for _, n := range normalizers {
+		step, ok := recognizeStep(n)
+		if !ok || len(steps) == maxFusedSteps {
+			flush()
+		}
+		if !ok {
+			segments = append(segments, n)
+			continue
+		}
+		steps = append(steps, step)
+		pending = append(pending, n)
+	}
+	flush()
+
+	if len(segments) == 1 {
+		return segments[0]
+	}
+
+	return segments.Apply
+}
+
+// Compile builds the steps like Normalizers and compiles them like Compile.
+func (s Spec) Compile() (Normalizer, error) {
+	normalizers, err := s.Normalizers()
+	if err != nil {
+		return nil, err
+	}
+
+	return Compile(normalizers), nil
+}
+
+// stepKind tells how a runeStep maps its input.
+type stepKind int
+
+const (
+	// stepReplace replaces the runes selected by match.
+	stepReplace stepKind = iota
+	// stepMap maps every rune like strings.Map.
+	stepMap
+	// stepFoldAccents works like foldAccents.
+	stepFoldAccents
+	// stepCaseFold works like CaseFoldNormalizer.
+	stepCaseFold
+)
+
+// runeStep is a normalizer that maps its input one rune at a time, with at
+// most one bit of state, so several of them can run in a single pass.
+type runeStep struct {
+	kind stepKind
+	// match selects the runes replaced with replacement by stepReplace.
+	// Every run of them is replaced once when runs is set.
+	match       func(rune) bool
+	replacement string
+	runs        bool
+	// mapping is the function of stepMap.
+	mapping func(rune) rune
+}
+
+// maxFusedSteps is the number of steps whose state fits in a fusedPass.
+const maxFusedSteps = 64
+
+func isRune(want rune) func(rune) bool {
+	return func(r rune) bool {
+		return r == want
+	}
+}
+
+// recognizeStep returns the step of a built-in normalizer that works one rune
+// at a time.
+func recognizeStep(n Normalizer) (runeStep, bool) {
+	if d := describe(n); d != nil && d.fusible {
+		return d.step, true
+	}
+
+	return runeStep{}, false
+}
+
+// fuse returns a Normalizer running the steps of the normalizers in a single
+// pass.
+//
+// The pass decodes the input once, but a step removing runes can join the
+// bytes of invalid UTF-8 around them into a rune that the next normalizer
+// decodes. A step replacing runes with invalid UTF-8 can do the same. Such
+// steps run on their own when the input is invalid, or always for invalid
+// replacements, which are rare.
+func fuse(steps []runeStep, normalizers Normalizers) Normalizer {
+	joins := false
+	for _, s := range steps[:len(steps)-1] {
+		if s.kind != stepReplace {
+			continue
+		}
+		if !utf8.ValidString(s.replacement) {
+			return normalizers.Apply
+		}
+		joins = joins || s.replacement == ""
+	}
+
+	return func(input string) string {
+		p := fusedPass{steps: steps, input: input, validOnly: joins}
+		if !p.run() {
+			return normalizers.Apply(input)
+		}
+
+		return p.result()
+	}
+}
+
+// fusedPass runs one string through the steps. Each step hands its output to
+// the next one rune at a time, and the last one writes it. The output is only
+// copied once it differs from the input, unless it is appended to dst.
+type fusedPass struct {
+	steps []runeStep
+	// state holds the one bit of state of every step.
+	state uint64
+	input string
+	// validOnly stops the pass at invalid UTF-8.
+	validOnly bool
+	// same is the length of the output while it is a prefix of the input.
+	same      int
+	diverged  bool
+	b         strings.Builder
+	appending bool
+	dst       []byte
+}
+
+// run runs the pass over the input, and reports whether it did. It stops at
+// invalid UTF-8 when validOnly is set.
+func (p *fusedPass) run() bool {
+	for i := 0; i < len(p.input); {
+		r, size := rune(p.input[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(p.input[i:])
+			if p.validOnly && size == 1 {
+				return false
+			}
+		}
+		p.push(0, r, p.input[i:i+size])
+		i += size
+	}
+
+	return true
+}
+
+// push hands r to step k. raw holds the bytes of r in the input, which differ
+// from its encoding for invalid UTF-8, or is empty when r must be encoded.
+func (p *fusedPass) push(k int, r rune, raw string) {
+	if k == len(p.steps) {
+		p.write(r, raw)
+		return
+	}
+
+	s := &p.steps[k]
+	bit := uint64(1) << k
+	switch s.kind {
+	case stepReplace:
+		if !s.match(r) {
+			p.state &^= bit
+			p.push(k+1, r, raw)
+			return
+		}
+		if s.runs && p.state&bit != 0 {
+			return
+		}
+		p.state |= bit
+		p.pushString(k+1, s.replacement)
+
+	case stepMap:
+		// strings.Map writes invalid bytes as utf8.RuneError.
+		if m := s.mapping(r); m != r || r == utf8.RuneError && len(raw) == 1 {
+			r, raw = m, ""
+		}
+		p.push(k+1, r, raw)
+
+	case stepFoldAccents:
+		if r < utf8.RuneSelf {
+			// ASCII letters have nothing to fold but keep the marks after them out.
+			if isASCIIAlphanumeric(byte(r)) && !isASCIIDigit(r) {
+				p.state |= bit
+			} else {
+				p.state &^= bit
+			}
+			p.push(k+1, r, raw)
+			return
+		}
+		if unicode.Is(unicode.Mn, r) {
+			if p.state&bit == 0 {
+				p.push(k+1, r, raw)
+			}
+			return
+		}
+		if !isFoldableScript(r) {
+			p.state &^= bit
+			p.push(k+1, r, raw)
+			return
+		}
+		p.state |= bit
+		if d, ok := canonicalDecompositionTable[r]; ok {
+			r, _ = utf8.DecodeRuneInString(d)
+			raw = ""
+		}
+		if f, ok := foldTable[r]; ok {
+			p.pushString(k+1, f)
+			return
+		}
+		p.push(k+1, r, raw)
+
+	case stepCaseFold:
+		switch {
+		case r >= 'A' && r <= 'Z':
+			p.push(k+1, r+'a'-'A', "")
+		case r < utf8.RuneSelf:
+			p.push(k+1, r, raw)
+		default:
+			if f, ok := caseFoldTable[r]; ok {
+				p.pushString(k+1, f)
+				return
+			}
+			p.push(k+1, r, raw)
+		}
+	}
+}
+
+// pushString hands every rune of s to step k.
+func (p *fusedPass) pushString(k int, s string) {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		p.push(k, r, s[i:i+size])
+		i += size
+	}
+}
+
+func (p *fusedPass) write(r rune, raw string) {
+	if p.appending {
+		if raw == "" {
+			p.dst = utf8.AppendRune(p.dst, r)
+		} else {
+			p.dst = append(p.dst, raw...)
+		}
+		return
+	}
+	if p.diverged {
+		if raw == "" {
+			p.b.WriteRune(r)
+		} else {
+			p.b.WriteString(raw)
+		}
+		return
+	}
+
+	var buf [utf8.UTFMax]byte
+	if raw == "" {
+		raw = string(buf[:utf8.EncodeRune(buf[:], r)])
+	}
+	if strings.HasPrefix(p.input[p.same:], raw) {
+		p.same += len(raw)
+		return
+	}
+	p.diverged = true
+	p.b.Grow(len(p.input))
+	p.b.WriteString(p.input[:p.same])
+	p.b.WriteString(raw)
+}
+
+func (p *fusedPass) result() string {
+	if !p.diverged {
+		return p.input[:p.same]
+	}
+
+	return p.b.String()
+}
@@ -0,0 +1,233 @@
+package textn8r
+
+import (
+	"strings"
+	"testing"
+)
+
+// compileSteps are the normalizers FuzzCompile builds pipelines from. The
+// first fusibleCompileSteps of them are fusible.
+var compileSteps = []Normalizer{
+	UpperCaseNormalizer,
+	LowerCaseNormalizer,
+	CaseFoldNormalizer,
+	ReplaceAccentsNormalizer,
+	RemoveAllSpaceNormalizer,
+	RemoveCarriageReturnNormalizer,
+	RemoveNewLineNormalizer,
+	RemoveTabNormalizer,
+	RemoveTildesNormalizer,
+	RemoveNonAlphanumericNormalizer,
+	RemoveDiacriticsNormalizer,
+	RemovePunctuationNormalizer,
+	RemoveDigitsNormalizer,
+	RemoveSpecialCharactersNormalizer,
+	ReplaceTildesNormalizer,
+	ReplaceTabNormalizer("-"),
+	ReplaceCarriageReturnNormalizer("\t"),
+	ReplaceNewLineNormalizer("é"),
+	ReplaceNonAlphanumericNormalizer("-"),
+	ReplacePunctuationNormalizer("~"),
+	ReplaceDigitsNormalizer("#"),
+	ReplaceSpaceNormalizer("_"),
+	ReplaceDiacriticsNormalizer("?"),
+	ReplaceDiacriticsNormalizer(""),
+	// The others are not fusible.
+	TrimSpaceNormalizer,
+	RemoveExtraSpaceNormalizer,
+	NFDNormalizer,
+	func(input string) string { return strings.ReplaceAll(input, "a", "Á") },
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		normalizers Normalizers
+		input       string
+		expected    string
+	}{
+		{
+			Normalizers{TrimSpaceNormalizer, LowerCaseNormalizer, ReplaceAccentsNormalizer, ReplaceSpaceNormalizer("-")},
+			"  Crème Brûlée à la Carte ",
+			"creme-brulee-a-la-carte",
+		},
+		{
+			Normalizers{ReplaceTabNormalizer(" "), ReplaceNewLineNormalizer(" "), RemoveDigitsNormalizer, UpperCaseNormalizer},
+			"line 1\tline 2\nline 3",
+			"LINE  LINE  LINE ",
+		},
+		{
+			Normalizers{ReplaceTabNormalizer("é"), ReplaceAccentsNormalizer},
+			"a\tb",
+			"aeb",
+		},
+		{
+			Normalizers{RemoveDiacriticsNormalizer, UpperCaseNormalizer},
+			"héllo wörld",
+			"HLLO WRLD",
+		},
+		{
+			Normalizers{UpperCaseNormalizer, RemoveTabNormalizer},
+			"a\xffb\tc",
+			"A�BC",
+		},
+		{
+			// Removing "!" joins the invalid bytes around it into "ѿ".
+			Normalizers{RemovePunctuationNormalizer, UpperCaseNormalizer},
+			"\xee\xd1!\xbf\xc3",
+			"\ufffdѾ\ufffd",
+		},
+		{
+			Normalizers{ReplaceTabNormalizer("\xd1"), ReplaceNewLineNormalizer("\xbf"), UpperCaseNormalizer},
+			"\t\n",
+			"Ѿ",
+		},
+		{
+			Normalizers{RemoveTabNormalizer, RemoveNewLineNormalizer},
+			"unchanged",
+			"unchanged",
+		},
+		{
+			Normalizers{RemoveTabNormalizer, RemoveNewLineNormalizer},
+			"trailing\t\n",
+			"trailing",
+		},
+		{Normalizers{}, "input", "input"},
+	}
+
+	for _, tt := range tests {
+		result := Compile(tt.normalizers)(tt.input)
+		if result != tt.expected {
+			t.Errorf("Compile(...)(%+q) = %+q; want %+q", tt.input, result, tt.expected)
+		}
+		if applied := tt.normalizers.Apply(tt.input); result != applied {
+			t.Errorf("Compile(...)(%+q) = %+q; Apply gives %+q", tt.input, result, applied)
+		}
+	}
+}
+
+const fusibleCompileSteps = 24
+
+func TestCompileRecognizesBuiltins(t *testing.T) {
+	for i, n := range compileSteps {
+		if _, ok := recognizeStep(n); ok != (i < fusibleCompileSteps) {
+			t.Errorf("compileSteps[%d] recognized as fusible = %v; want %v", i, ok, !ok)
+		}
+	}
+}
+
+func TestSpecCompile(t *testing.T) {
+	tests := []struct {
+		src      string
+		input    string
+		expected string
+	}{
+		{`trim | lower | fold_accents | replace_space("-")`, "  Crème Brûlée à la Carte ", "creme-brulee-a-la-carte"},
+		{`replace_tab(" ") | replace_new_line(" ") | remove_digits | upper`, "line 1\tline 2\nline 3", "LINE  LINE  LINE "},
+		{`replace_tab("é") | fold_accents`, "a\tb", "aeb"},
+		{`replace_special_characters("_") | replace_diacritics("?") | title_case`, "¡hola, señor!", "_Hola_ Se?or_"},
+	}
+
+	for _, tt := range tests {
+		spec, err := ParseSpec(tt.src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compiled, err := spec.Compile()
+		if err != nil {
+			t.Fatalf("%s: Compile error = %v", tt.src, err)
+		}
+		if result := compiled(tt.input); result != tt.expected {
+			t.Errorf("%s: compiled(%+q) = %+q; want %+q", tt.src, tt.input, result, tt.expected)
+		}
+		normalizers, _ := spec.Normalizers()
+		if result, applied := compiled(tt.input), normalizers.Apply(tt.input); result != applied {
+			t.Errorf("%s: compiled(%+q) = %+q; Apply gives %+q", tt.src, tt.input, result, applied)
+		}
+	}
+
+	for name, args := range map[string]Args{
+		"replace_special_characters": {"replacement": "-"},
+		"replace_tab":                {"replacement": "-"},
+		"replace_carriage_return":    {"replacement": "-"},
+		"replace_new_line":           {"replacement": "-"},
+		"replace_non_alphanumeric":   {"replacement": "-"},
+		"replace_punctuation":        {"replacement": "-"},
+		"replace_digits":             {"replacement": "-"},
+		"replace_space":              {"replacement": "-"},
+		"replace_diacritics":         {"replacement": "-"},
+	} {
+		n, err := BuildNormalizer(name, args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := recognizeStep(n); !ok {
+			t.Errorf("%s is not fused by Spec.Compile", name)
+		}
+	}
+	if _, err := (Spec{{Name: "replace_tab"}}).Compile(); err == nil {
+		t.Errorf("Compile without the replacement of replace_tab succeeded")
+	}
+}
+
+func TestCompileDoesNotAllocateWhenUnchanged(t *testing.T) {
+	compiled := Compile(Normalizers{RemoveTabNormalizer, RemoveNewLineNormalizer, ReplaceAccentsNormalizer, LowerCaseNormalizer})
+	input := "already normalized text"
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = compiled(input)
+	})
+	if allocs != 0 {
+		t.Errorf("compiled normalizer allocated %v times; want 0", allocs)
+	}
+}
+
+// FuzzCompile checks that compiled pipelines give the same result as Apply.
+// Every byte of program selects a step from compileSteps.
+func FuzzCompile(f *testing.F) {
+	f.Add("  Crème Brûlée à la Carte ", []byte{24, 1, 3, 21})
+	f.Add("line 1\tline 2\nline 3\r\n", []byte{15, 16, 17, 12, 0})
+	f.Add("Ærøskøbing ŁÓDŹ Ὀδυσσεύς Straße ﬁ", []byte{2, 3, 18, 0, 1})
+	f.Add("a\xffb\xe2\x82 ć́ ~ñ", []byte{0, 3, 9, 14, 8, 22, 23})
+	f.Add("¿¡Hola!? ①②③ 42%", []byte{11, 13, 19, 20, 10})
+	f.Add("naïve café", []byte{27, 3, 26, 3, 25, 4})
+	f.Add("\xee\xd1!\xbf\xc3", []byte{11, 0})
+
+	f.Fuzz(func(t *testing.T, input string, program []byte) {
+		normalizers := make(Normalizers, len(program))
+		for i, b := range program {
+			normalizers[i] = compileSteps[int(b)%len(compileSteps)]
+		}
+
+		expected := normalizers.Apply(input)
+		if result := Compile(normalizers)(input); result != expected {
+			t.Errorf("Compile(%v)(%+q) = %+q; want %+q", program, input, result, expected)
+		}
+	})
+}
+
+func BenchmarkCompile(b *testing.B) {
+	normalizers := Normalizers{
+		LowerCaseNormalizer,
+		ReplaceAccentsNormalizer,
+		ReplaceTabNormalizer(" "),
+		ReplaceNewLineNormalizer(" "),
+		RemoveCarriageReturnNormalizer,
+		RemoveDigitsNormalizer,
+	}
+	compiled := Compile(normalizers)
+
+	b.Run("Apply", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(benchmarkInput)))
+		for i := 0; i < b.N; i++ {
+			_ = normalizers.Apply(benchmarkInput)
+		}
+	})
+	b.Run("Compile", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(benchmarkInput)))
+		for i := 0; i < b.N; i++ {
+			_ = compiled(benchmarkInput)
+		}
+	})
+}
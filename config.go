@@ -0,0 +1,221 @@
+package textn8r
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ConfigVersion is the version of the JSON format of pipelines written by
+// MarshalJSON.
+const ConfigVersion = 1
+
+// config is the JSON format of pipelines.
+type config struct {
+	Version int        `json:"version"`
+	Steps   []StepSpec `json:"steps"`
+}
+
+// ConfigError reports an invalid JSON pipeline.
+type ConfigError struct {
+	// Path locates the problem, such as "steps[2].args.replacement". It is
+	// empty for problems with the whole document.
+	Path string
+	Msg  string
+	// Err is the error behind the problem, if any, such as an
+	// *UnknownNormalizerError.
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Path == "" {
+		return "textn8r: invalid pipeline: " + e.Msg
+	}
+
+	return "textn8r: invalid pipeline: " + e.Path + ": " + e.Msg
+}
+
+// Unwrap returns the error behind the problem, if any.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON writes the spec as
+//
+//	{"version": 1, "steps": [{"name": "replace_tab", "args": {"replacement": " "}}]}
+//
+// leaving out arguments of registered normalizers that have their default
+// value.
+func (s Spec) MarshalJSON() ([]byte, error) {
+	steps := make([]StepSpec, len(s))
+	for i, step := range s {
+		steps[i] = step.compact()
+	}
+
+	return json.Marshal(config{Version: ConfigVersion, Steps: steps})
+}
+
+// UnmarshalJSON reads a spec written by MarshalJSON. A bare list of steps is
+// read as version 1. Unknown fields, unsupported versions, unregistered names
+// and invalid arguments are reported as a *ConfigError.
+func (s *Spec) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+
+	var c config
+	if len(data) > 0 && data[0] == '[' {
+		c.Version = 1
+		if err := decodeStrict(data, &c.Steps); err != nil {
+			return configError(err)
+		}
+	} else {
+		var raw struct {
+			Version *int       `json:"version"`
+			Steps   []StepSpec `json:"steps"`
+		}
+		if err := decodeStrict(data, &raw); err != nil {
+			return configError(err)
+		}
+		if raw.Version == nil {
+			return &ConfigError{Msg: "missing version"}
+		}
+		if raw.Steps == nil {
+			return &ConfigError{Msg: "missing steps"}
+		}
+		c.Version, c.Steps = *raw.Version, raw.Steps
+	}
+	if c.Version != ConfigVersion {
+		return &ConfigError{Path: "version", Msg: "unsupported version " + strconv.Itoa(c.Version)}
+	}
+
+	spec := make(Spec, len(c.Steps))
+	for i, step := range c.Steps {
+		path := "steps[" + strconv.Itoa(i) + "]"
+		if step.Name == "" {
+			return &ConfigError{Path: path, Msg: "missing name"}
+		}
+		d, ok := Lookup(step.Name)
+		if !ok {
+			err := &UnknownNormalizerError{Name: step.Name}
+			return &ConfigError{Path: path + ".name", Msg: strings.TrimPrefix(err.Error(), "textn8r: "), Err: err}
+		}
+
+		checked, err := d.check(step.Args)
+		if err != nil {
+			var argErr *ArgumentError
+			if errors.As(err, &argErr) {
+				path += ".args." + argErr.Param
+				return &ConfigError{Path: path, Msg: argErr.Reason, Err: err}
+			}
+			return &ConfigError{Path: path + ".args", Msg: err.Error(), Err: err}
+		}
+
+		// Keep the given arguments, converted to the parameter types.
+		var args Args
+		for name := range step.Args {
+			if args == nil {
+				args = Args{}
+			}
+			args[name] = checked[name]
+		}
+		spec[i] = StepSpec{Name: step.Name, Args: args}
+	}
+	*s = spec
+
+	return nil
+}
+
+// decodeStrict decodes a single JSON value, rejecting unknown fields.
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after the pipeline")
+	}
+
+	return nil
+}
+
+func configError(err error) *ConfigError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &ConfigError{Path: typeErr.Field, Msg: "want " + typeErr.Type.String() + ", got " + typeErr.Value, Err: err}
+	}
+
+	return &ConfigError{Msg: strings.TrimPrefix(err.Error(), "json: "), Err: err}
+}
+
+// compact returns the step without the arguments of a registered normalizer
+// that are optional and have their default value.
+func (s StepSpec) compact() StepSpec {
+	d, ok := Lookup(s.Name)
+	if !ok {
+		return s
+	}
+
+	var args Args
+	for name, value := range s.Args {
+		if i := d.paramIndex(name); i >= 0 && !d.Params[i].Required &&
+			formatValue(value) == formatValue(paramDefault(d.Params[i])) {
+			continue
+		}
+		if args == nil {
+			args = Args{}
+		}
+		args[name] = value
+	}
+
+	return StepSpec{Name: s.Name, Args: args}
+}
+
+// SpecOf returns the spec of normalizers that FormatNormalizers can write,
+// or a *FormatError.
+func SpecOf(normalizers Normalizers) (Spec, error) {
+	spec := make(Spec, len(normalizers))
+	for i, n := range normalizers {
+		d := describe(n)
+		if d == nil || d.spec.Name == "" {
+			return nil, &FormatError{Index: i}
+		}
+		spec[i] = d.spec.compact()
+	}
+
+	return spec, nil
+}
+
+// MarshalJSON writes the normalizers in the format of Spec.MarshalJSON, so
+// that UnmarshalJSON gives them back. It returns a *FormatError for
+// normalizers that FormatNormalizers cannot write, such as those returned by
+// constructors with parameters; marshal their Spec instead.
+func (n Normalizers) MarshalJSON() ([]byte, error) {
+	spec, err := SpecOf(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return spec.MarshalJSON()
+}
+
+// UnmarshalJSON reads normalizers in the format of Spec.UnmarshalJSON and
+// builds them with BuildNormalizer.
+func (n *Normalizers) UnmarshalJSON(data []byte) error {
+	var spec Spec
+	if err := spec.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	normalizers, err := spec.Normalizers()
+	if err != nil {
+		return &ConfigError{Msg: strings.TrimPrefix(err.Error(), "textn8r: "), Err: err}
+	}
+	*n = normalizers
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package textn8r
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSpecJSON(t *testing.T) {
+	spec, err := ParseSpec(`trim | replace_space("-") | slug("_", 40) | title_case(exceptions=["NASA"])`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"version":1,"steps":[{"name":"trim"},{"name":"replace_space","args":{"replacement":"-"}},` +
+		`{"name":"slug","args":{"max_length":40,"separator":"_"}},{"name":"title_case","args":{"exceptions":["NASA"]}}]}`
+	if string(data) != expected {
+		t.Errorf("Marshal() = %s; want %s", data, expected)
+	}
+
+	var decoded Spec
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, spec) {
+		t.Errorf("Unmarshal(%s) = %#v; want %#v", data, decoded, spec)
+	}
+}
+
+func TestSpecUnmarshalJSONList(t *testing.T) {
+	var spec Spec
+	if err := json.Unmarshal([]byte(`[{"name": "lower"}, {"name": "replace_tab", "args": {"replacement": " "}}]`), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.String() != `lower | replace_tab(" ")` {
+		t.Errorf("Unmarshal() = %v", spec)
+	}
+}
+
+func TestSpecUnmarshalJSONErrors(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{`{"steps": []}`, "textn8r: invalid pipeline: missing version"},
+		{`{"version": 1}`, "textn8r: invalid pipeline: missing steps"},
+		{`{"version": 2, "steps": []}`, "textn8r: invalid pipeline: version: unsupported version 2"},
+		{`{"version": 1, "steps": [], "mode": "stop"}`, `textn8r: invalid pipeline: unknown field "mode"`},
+		{`{"version": "1", "steps": []}`, "textn8r: invalid pipeline: version: want int, got string"},
+		{`[{"name": "trim", "arg": {}}]`, `textn8r: invalid pipeline: unknown field "arg"`},
+		{`[{"name": "trim"}, {"args": {}}]`, "textn8r: invalid pipeline: steps[1]: missing name"},
+		{`[{"name": "lowr"}]`, `textn8r: invalid pipeline: steps[0].name: unknown normalizer "lowr"`},
+		{`[{"name": "replace_tab"}]`, "textn8r: invalid pipeline: steps[0].args.replacement: missing required string"},
+		{`[{"name": "slug", "args": {"max_length": 1.5}}]`, "textn8r: invalid pipeline: steps[0].args.max_length: want int"},
+		{`[{"name": "trim", "args": {"cutset": " "}}]`, "textn8r: invalid pipeline: steps[0].args.cutset: unknown parameter"},
+		{`[] []`, "textn8r: invalid pipeline: unexpected data after the pipeline"},
+	}
+
+	for _, tt := range tests {
+		var spec Spec
+		err := spec.UnmarshalJSON([]byte(tt.data))
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) {
+			t.Errorf("UnmarshalJSON(%s) error = %v; want a *ConfigError", tt.data, err)
+			continue
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("UnmarshalJSON(%s) error = %q; want %q", tt.data, err.Error(), tt.expected)
+		}
+	}
+
+	var spec Spec
+	err := spec.UnmarshalJSON([]byte(`[{"name": "lowr"}]`))
+	var unknown *UnknownNormalizerError
+	if !errors.As(err, &unknown) || unknown.Name != "lowr" {
+		t.Errorf("UnmarshalJSON error = %v; want an *UnknownNormalizerError", err)
+	}
+}
+
+func TestNormalizersJSON(t *testing.T) {
+	var rules struct {
+		Customer   string      `json:"customer"`
+		Normalizer Normalizers `json:"normalizer"`
+	}
+	data := `{"customer": "acme", "normalizer": {"version": 1, "steps": [
+		{"name": "trim"}, {"name": "lower"}, {"name": "replace_space", "args": {"replacement": "-"}}
+	]}}`
+	if err := json.Unmarshal([]byte(data), &rules); err != nil {
+		t.Fatal(err)
+	}
+	if result := rules.Normalizer.Apply("  Hello World "); result != "hello-world" {
+		t.Errorf("Apply() = %q; want %q", result, "hello-world")
+	}
+
+	encoded, err := json.Marshal(rules)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	expected := `{"customer":"acme","normalizer":{"version":1,"steps":[` +
+		`{"name":"trim"},{"name":"lower"},{"name":"replace_space","args":{"replacement":"-"}}]}}`
+	if string(encoded) != expected {
+		t.Errorf("Marshal() = %s; want %s", encoded, expected)
+	}
+
+	// Reading the output back gives the same normalizers.
+	var decoded struct {
+		Customer   string      `json:"customer"`
+		Normalizer Normalizers `json:"normalizer"`
+	}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", encoded, err)
+	}
+	if result := decoded.Normalizer.Apply("  Hello World "); result != "hello-world" {
+		t.Errorf("Apply() after the round trip = %q; want %q", result, "hello-world")
+	}
+	if reencoded, err := json.Marshal(decoded); err != nil || string(reencoded) != expected {
+		t.Errorf("Marshal() after the round trip = %s, %v; want %s", reencoded, err, expected)
+	}
+
+	// The parameters of a normalizer returned by a constructor cannot be read back.
+	var formatErr *FormatError
+	withTab := append(rules.Normalizer[:2:2], ReplaceTabNormalizer(" "))
+	if _, err := json.Marshal(withTab); !errors.As(err, &formatErr) || formatErr.Index != 2 {
+		t.Errorf("Marshal() error = %v; want a *FormatError for #2", err)
+	}
+
+	custom := Normalizers{func(input string) string { return input }}
+	if _, err := json.Marshal(custom); err == nil {
+		t.Errorf("Marshal() of a custom normalizer succeeded; want an error")
+	}
+}
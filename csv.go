@@ -0,0 +1,398 @@
+package textn8r
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// utf8BOM is the byte order mark some tools write at the start of UTF-8
+// files.
+const utf8BOM = "\uFEFF"
+
+// CSVColumn selects a column of a CSVNormalizer and the normalizer applied to
+// its fields.
+type CSVColumn struct {
+	// Header selects the column by its name in the header record.
+	Header string
+	// Index selects the column by its position, from 0, when Header is empty.
+	Index      int
+	Normalizer NormalizerE
+}
+
+// name returns the header of the column, or its position.
+func (c CSVColumn) name() string {
+	if c.Header != "" {
+		return c.Header
+	}
+
+	return "#" + strconv.Itoa(c.Index)
+}
+
+// CSVNormalizer normalizes selected columns of CSV and TSV documents. Every
+// byte outside the changed fields is copied unchanged, including quotes,
+// delimiters, line endings, blank lines and a leading byte order mark.
+// Changed fields keep their quotes, and are quoted when their new value
+// needs it.
+//
+// Quoted fields follow RFC 4180. Quotes inside unquoted fields, and text after
+// the closing quote of a field, are kept as part of the field.
+type CSVNormalizer struct {
+	// Comma is the field delimiter, ',' when zero. Use '\t' for TSV.
+	Comma rune
+	// Header reports whether the first record names the columns. It is never
+	// normalized.
+	Header  bool
+	Columns []CSVColumn
+	// Mode selects what happens when a normalizer fails. In StopOnError mode
+	// Normalize returns a *CSVError. In CollectErrors mode the field is left
+	// unchanged and the error is added to CSVStats.Errors.
+	Mode ErrorMode
+}
+
+// CSVStats counts the fields changed by a CSVNormalizer.
+type CSVStats struct {
+	// Records is the number of records after the header.
+	Records int
+	// Columns holds the counts of each of CSVNormalizer.Columns.
+	Columns []CSVColumnStats
+	// Errors holds the fields rejected in CollectErrors mode.
+	Errors []*CSVError
+}
+
+// CSVColumnStats counts the fields of a column changed by a CSVNormalizer.
+type CSVColumnStats struct {
+	// Column is the header of the column, or its position as in "#2".
+	Column string
+	// Index is the position of the column.
+	Index   int
+	Changed int
+	Failed  int
+}
+
+// CSVError reports a problem with a CSV document.
+type CSVError struct {
+	// Line is the line where the record starts, from 1, or 0 for problems
+	// with the columns.
+	Line int
+	// Column is the header or position of the column, if the problem is
+	// about one.
+	Column string
+	Err    error
+}
+
+func (e *CSVError) Error() string {
+	msg := "textn8r: csv: "
+	if e.Line > 0 {
+		msg += "line " + strconv.Itoa(e.Line) + ": "
+	}
+	if e.Column != "" {
+		msg += "column " + strconv.Quote(e.Column) + ": "
+	}
+
+	return msg + strings.TrimPrefix(e.Err.Error(), "textn8r: ")
+}
+
+// Unwrap returns the error behind the problem.
+func (e *CSVError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrCSVQuote reports a quoted field missing its closing quote.
+	ErrCSVQuote = errors.New("unterminated quoted field")
+	// ErrCSVColumn reports a column missing from the header record.
+	ErrCSVColumn = errors.New("not in the header")
+)
+
+// csvField is a field of a record, from start to end in the raw record.
+type csvField struct {
+	start, end int
+	quoted     bool
+}
+
+// csvColumn is a column of a CSVNormalizer resolved to its position.
+type csvColumn struct {
+	index      int
+	name       string
+	normalizer NormalizerE
+	stats      *CSVColumnStats
+}
+
+// Normalize copies the document read from r to w, normalizing the fields of
+// the selected columns, and returns the counts of changed fields. Records
+// too short to hold a column are left unchanged.
+func (c *CSVNormalizer) Normalize(w io.Writer, r io.Reader) (*CSVStats, error) {
+	comma := c.Comma
+	if comma == 0 {
+		comma = ','
+	}
+	if comma == '"' || comma == '\r' || comma == '\n' || !utf8.ValidRune(comma) {
+		return nil, &ArgumentError{Normalizer: "csv", Param: "Comma", Reason: "invalid delimiter " + strconv.QuoteRune(comma)}
+	}
+
+	stats := &CSVStats{Columns: make([]CSVColumnStats, len(c.Columns))}
+	for i, column := range c.Columns {
+		stats.Columns[i] = CSVColumnStats{Column: column.name(), Index: column.Index}
+	}
+
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	s := &csvScanner{r: br, comma: []byte(string(comma)), line: 1}
+	if bom, _ := br.Peek(len(utf8BOM)); string(bom) == utf8BOM {
+		br.Discard(len(utf8BOM))
+		bw.WriteString(utf8BOM)
+	}
+
+	columns, err := c.normalize(bw, s, stats)
+	if err == nil && columns == nil && c.Header {
+		// An empty document has no header to find the columns in.
+		_, err = c.resolve(nil, nil, stats)
+	}
+	if flushErr := bw.Flush(); err == nil {
+		err = flushErr
+	}
+
+	return stats, err
+}
+
+func (c *CSVNormalizer) normalize(w *bufio.Writer, s *csvScanner, stats *CSVStats) ([]csvColumn, error) {
+	var columns []csvColumn
+	var out []byte
+	for {
+		line := s.line
+		raw, fields, err := s.next()
+		if err == io.EOF {
+			return columns, nil
+		}
+		if err != nil {
+			return columns, err
+		}
+		if len(fields) == 1 && fields[0].start == fields[0].end && !fields[0].quoted {
+			// Blank lines are not records.
+			w.Write(raw)
+			continue
+		}
+
+		if columns == nil {
+			var header []csvField
+			if c.Header {
+				header = fields
+			}
+			if columns, err = c.resolve(raw, header, stats); err != nil {
+				return columns, err
+			}
+			if c.Header {
+				w.Write(raw)
+				continue
+			}
+		}
+		stats.Records++
+
+		out = out[:0]
+		copied := 0
+		for _, column := range columns {
+			if column.index >= len(fields) {
+				continue
+			}
+			field := fields[column.index]
+			value := csvFieldValue(raw, field)
+			result, err := column.normalizer(value)
+			if err != nil {
+				column.stats.Failed++
+				csvErr := &CSVError{Line: line, Column: column.name, Err: err}
+				if c.Mode != CollectErrors {
+					w.Write(out)
+					w.Write(raw[copied:])
+					return columns, csvErr
+				}
+				stats.Errors = append(stats.Errors, csvErr)
+				continue
+			}
+			if result == value {
+				continue
+			}
+
+			column.stats.Changed++
+			out = append(out, raw[copied:field.start]...)
+			out = appendCSVField(out, result, field.quoted, s.comma)
+			copied = field.end
+		}
+		if copied == 0 {
+			w.Write(raw)
+			continue
+		}
+		out = append(out, raw[copied:]...)
+		w.Write(out)
+	}
+}
+
+// resolve finds the positions of the columns, in the order of the fields,
+// in the header or, without header, in the first record.
+func (c *CSVNormalizer) resolve(raw []byte, header []csvField, stats *CSVStats) ([]csvColumn, error) {
+	names := map[string]int{}
+	for i, field := range header {
+		name := csvFieldValue(raw, field)
+		if _, ok := names[name]; !ok {
+			names[name] = i
+		}
+	}
+
+	columns := make([]csvColumn, len(c.Columns))
+	seen := map[int]string{}
+	for i, column := range c.Columns {
+		index := column.Index
+		if column.Header != "" {
+			var ok bool
+			if index, ok = names[column.Header]; !ok {
+				return nil, &CSVError{Column: column.Header, Err: ErrCSVColumn}
+			}
+		}
+		if index < 0 {
+			return nil, &CSVError{Column: column.name(), Err: errors.New("negative index")}
+		}
+		if other, ok := seen[index]; ok {
+			return nil, &CSVError{Column: column.name(), Err: errors.New("same column as " + strconv.Quote(other))}
+		}
+		seen[index] = column.name()
+
+		stats.Columns[i].Index = index
+		columns[i] = csvColumn{index: index, name: column.name(), normalizer: column.Normalizer, stats: &stats.Columns[i]}
+	}
+
+	// Sort by position, so records are rewritten in one pass.
+	for i := 1; i < len(columns); i++ {
+		for j := i; j > 0 && columns[j].index < columns[j-1].index; j-- {
+			columns[j], columns[j-1] = columns[j-1], columns[j]
+		}
+	}
+
+	return columns, nil
+}
+
+// csvScanner splits a document into raw records.
+type csvScanner struct {
+	r     *bufio.Reader
+	comma []byte
+	// line is the line where the next record starts.
+	line int
+	buf  []byte
+}
+
+// next returns the next record, including its line ending, and its fields.
+func (s *csvScanner) next() ([]byte, []csvField, error) {
+	s.buf = s.buf[:0]
+	for {
+		chunk, err := s.r.ReadSlice('\n')
+		s.buf = append(s.buf, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+		if len(s.buf) == 0 {
+			return nil, nil, io.EOF
+		}
+
+		fields, complete := parseCSVRecord(s.buf, s.comma)
+		if complete {
+			s.line += bytes.Count(s.buf, []byte{'\n'})
+			return s.buf, fields, nil
+		}
+		if err == io.EOF {
+			return nil, nil, &CSVError{Line: s.line, Err: ErrCSVQuote}
+		}
+	}
+}
+
+// parseCSVRecord splits a raw record into fields. It reports false when the
+// record ends inside a quoted field.
+func parseCSVRecord(raw, comma []byte) ([]csvField, bool) {
+	var fields []csvField
+	pos := 0
+	for {
+		field := csvField{start: pos}
+		if pos < len(raw) && raw[pos] == '"' {
+			field.quoted = true
+			pos++
+			for {
+				i := bytes.IndexByte(raw[pos:], '"')
+				if i < 0 {
+					return nil, false
+				}
+				pos += i + 1
+				if pos < len(raw) && raw[pos] == '"' {
+					pos++
+					continue
+				}
+				break
+			}
+		}
+		for pos < len(raw) && !bytes.HasPrefix(raw[pos:], comma) && !isCSVLineEnd(raw[pos:]) {
+			pos++
+		}
+		field.end = pos
+		fields = append(fields, field)
+
+		if !bytes.HasPrefix(raw[pos:], comma) {
+			return fields, true
+		}
+		pos += len(comma)
+	}
+}
+
+// isCSVLineEnd reports whether b starts with the line ending of a record.
+func isCSVLineEnd(b []byte) bool {
+	return b[0] == '\n' || b[0] == '\r' && len(b) > 1 && b[1] == '\n'
+}
+
+// csvFieldValue returns the value of a field, without its quotes.
+func csvFieldValue(raw []byte, field csvField) string {
+	if !field.quoted {
+		return string(raw[field.start:field.end])
+	}
+
+	var sb strings.Builder
+	pos := field.start + 1
+	for {
+		i := bytes.IndexByte(raw[pos:field.end], '"')
+		sb.Write(raw[pos : pos+i])
+		pos += i + 1
+		if pos < field.end && raw[pos] == '"' {
+			sb.WriteByte('"')
+			pos++
+			continue
+		}
+		break
+	}
+	sb.Write(raw[pos:field.end])
+
+	return sb.String()
+}
+
+// appendCSVField appends a value, quoted if the field was or if the value
+// needs it.
+func appendCSVField(dst []byte, value string, quoted bool, comma []byte) []byte {
+	if !quoted && !strings.ContainsAny(value, "\"\r\n") && !strings.Contains(value, string(comma)) {
+		return append(dst, value...)
+	}
+
+	dst = append(dst, '"')
+	for {
+		i := strings.IndexByte(value, '"')
+		if i < 0 {
+			break
+		}
+		dst = append(dst, value[:i+1]...)
+		dst = append(dst, '"')
+		value = value[i+1:]
+	}
+	dst = append(dst, value...)
+
+	return append(dst, '"')
+}
@@ -0,0 +1,220 @@
+package textn8r
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// normalizeCSV runs the normalizer on the input.
+func normalizeCSV(t *testing.T, c *CSVNormalizer, input string) (string, *CSVStats, error) {
+	t.Helper()
+	var sb strings.Builder
+	stats, err := c.Normalize(&sb, strings.NewReader(input))
+	return sb.String(), stats, err
+}
+
+func TestCSVNormalizer(t *testing.T) {
+	lower := Normalizers{TrimSpaceNormalizer, LowerCaseNormalizer}.NormalizerE()
+	upper := Normalizer(UpperCaseNormalizer).NormalizerE()
+
+	tests := []struct {
+		name     string
+		c        CSVNormalizer
+		input    string
+		expected string
+	}{
+		{
+			"header",
+			CSVNormalizer{Header: true, Columns: []CSVColumn{{Header: "email", Normalizer: lower}}},
+			"name,email,age\nJane, JANE@EXAMPLE.COM ,007\n",
+			"name,email,age\nJane,jane@example.com,007\n",
+		},
+		{
+			"quoting kept",
+			CSVNormalizer{Header: true, Columns: []CSVColumn{{Header: "email", Normalizer: lower}}},
+			"\"name\",\"email\"\r\n\"Doe, Jane\",\"JANE@X.COM\"\r\n\"Bob \"\"B\"\"\",  \"B@X.COM\"\r\n",
+			"\"name\",\"email\"\r\n\"Doe, Jane\",\"jane@x.com\"\r\n\"Bob \"\"B\"\"\",\"\"\"b@x.com\"\"\"\r\n",
+		},
+		{
+			"quoting added",
+			CSVNormalizer{Columns: []CSVColumn{{Index: 1, Normalizer: Normalizer(ReplaceSpaceNormalizer(",")).NormalizerE()}}},
+			"a,b c,d\n",
+			"a,\"b,c\",d\n",
+		},
+		{
+			"quotes escaped",
+			CSVNormalizer{Columns: []CSVColumn{{Index: 0, Normalizer: Normalizer(ReplaceTabNormalizer(`"`)).NormalizerE()}}},
+			"\"a\tb\",c\n",
+			"\"a\"\"b\",c\n",
+		},
+		{
+			"several columns",
+			CSVNormalizer{Columns: []CSVColumn{{Index: 2, Normalizer: upper}, {Index: 0, Normalizer: upper}}},
+			"a,b,c\nd,e\n\nf\n",
+			"A,b,C\nD,e\n\nF\n",
+		},
+		{
+			"multi-line field",
+			CSVNormalizer{Columns: []CSVColumn{{Index: 0, Normalizer: upper}}},
+			"\"line one\nline two\",x\ny,z",
+			"\"LINE ONE\nLINE TWO\",x\nY,z",
+		},
+		{
+			"bom",
+			CSVNormalizer{Header: true, Columns: []CSVColumn{{Header: "id", Normalizer: upper}}},
+			"\uFEFFid,v\nab,cd\n",
+			"\uFEFFid,v\nAB,cd\n",
+		},
+		{
+			"tsv",
+			CSVNormalizer{Comma: '\t', Header: true, Columns: []CSVColumn{{Header: "b", Normalizer: upper}}},
+			"a\tb\nx,y\tz,w\n",
+			"a\tb\nx,y\tZ,W\n",
+		},
+		{
+			"multi-byte delimiter",
+			CSVNormalizer{Comma: '¦', Columns: []CSVColumn{{Index: 1, Normalizer: upper}}},
+			"a¦b¦c\n",
+			"a¦B¦c\n",
+		},
+		{
+			"lazy quotes",
+			CSVNormalizer{Columns: []CSVColumn{{Index: 0, Normalizer: upper}, {Index: 1, Normalizer: upper}}},
+			"a \"b\" c,\"d\" e\n",
+			"\"A \"\"B\"\" C\",\"D E\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		result, _, err := normalizeCSV(t, &tt.c, tt.input)
+		if err != nil {
+			t.Errorf("%s: Normalize() error = %v", tt.name, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("%s: Normalize(%q) = %q; want %q", tt.name, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestCSVNormalizerStats(t *testing.T) {
+	c := &CSVNormalizer{
+		Header: true,
+		Columns: []CSVColumn{
+			{Header: "email", Normalizer: Normalizer(LowerCaseNormalizer).NormalizerE()},
+			{Index: 0, Normalizer: Normalizer(TrimSpaceNormalizer).NormalizerE()},
+			{Header: "tag", Normalizer: requireAt},
+		},
+		Mode: CollectErrors,
+	}
+
+	input := "name,email,tag\n Jane ,JANE@X.COM,@a\nBob,bob@x.com,b\n"
+	result, stats, err := normalizeCSV(t, c, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "name,email,tag\nJane,jane@x.com,@a\nBob,bob@x.com,b\n"; result != expected {
+		t.Errorf("Normalize() = %q; want %q", result, expected)
+	}
+
+	if stats.Records != 2 {
+		t.Errorf("Records = %d; want 2", stats.Records)
+	}
+	expected := []CSVColumnStats{
+		{Column: "email", Index: 1, Changed: 1},
+		{Column: "#0", Index: 0, Changed: 1},
+		{Column: "tag", Index: 2, Failed: 1},
+	}
+	for i, column := range stats.Columns {
+		if column != expected[i] {
+			t.Errorf("Columns[%d] = %+v; want %+v", i, column, expected[i])
+		}
+	}
+	if len(stats.Errors) != 1 || stats.Errors[0].Line != 3 || stats.Errors[0].Column != "tag" {
+		t.Errorf("Errors = %v; want one error on line 3, column tag", stats.Errors)
+	}
+}
+
+func TestCSVNormalizerErrors(t *testing.T) {
+	upper := Normalizer(UpperCaseNormalizer).NormalizerE()
+	tests := []struct {
+		name     string
+		c        CSVNormalizer
+		input    string
+		output   string
+		expected string
+	}{
+		{
+			"missing column",
+			CSVNormalizer{Header: true, Columns: []CSVColumn{{Header: "mail", Normalizer: upper}}},
+			"name,email\n",
+			"",
+			`textn8r: csv: column "mail": not in the header`,
+		},
+		{
+			"empty document",
+			CSVNormalizer{Header: true, Columns: []CSVColumn{{Header: "mail", Normalizer: upper}}},
+			"",
+			"",
+			`textn8r: csv: column "mail": not in the header`,
+		},
+		{
+			"same column",
+			CSVNormalizer{Header: true, Columns: []CSVColumn{{Header: "b", Normalizer: upper}, {Index: 1, Normalizer: upper}}},
+			"a,b\n",
+			"",
+			`textn8r: csv: column "#1": same column as "b"`,
+		},
+		{
+			"unterminated quote",
+			CSVNormalizer{Columns: []CSVColumn{{Index: 0, Normalizer: upper}}},
+			"a\n\"b\nc\n",
+			"A\n",
+			`textn8r: csv: line 2: unterminated quoted field`,
+		},
+		{
+			"rejected field",
+			CSVNormalizer{Columns: []CSVColumn{{Index: 0, Normalizer: upper}, {Index: 1, Normalizer: requireAt}}},
+			"a,@\nb,c\nd,e\n",
+			"A,@\nB,c\n",
+			`textn8r: csv: line 2: column "#1": invalid input "c": missing "@"`,
+		},
+		{
+			"invalid delimiter",
+			CSVNormalizer{Comma: '"'},
+			"a\n",
+			"",
+			`textn8r: normalizer "csv": parameter "Comma": invalid delimiter '"'`,
+		},
+	}
+
+	for _, tt := range tests {
+		result, _, err := normalizeCSV(t, &tt.c, tt.input)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("%s: Normalize() error = %v; want %s", tt.name, err, tt.expected)
+		}
+		if result != tt.output {
+			t.Errorf("%s: Normalize() wrote %q; want %q", tt.name, result, tt.output)
+		}
+	}
+
+	_, _, err := normalizeCSV(t, &CSVNormalizer{Columns: []CSVColumn{{Index: 0, Normalizer: requireAt}}}, "x\n")
+	var invalid *InvalidInputError
+	if !errors.As(err, &invalid) {
+		t.Errorf("Normalize() error = %v; want an *InvalidInputError", err)
+	}
+}
+
+func TestCSVNormalizerLongRecords(t *testing.T) {
+	long := strings.Repeat("x", 10000)
+	c := &CSVNormalizer{Columns: []CSVColumn{{Index: 1, Normalizer: Normalizer(UpperCaseNormalizer).NormalizerE()}}}
+	result, _, err := normalizeCSV(t, c, long+",\""+long+"\n"+long+"\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upper := strings.ToUpper(long)
+	if expected := long + ",\"" + upper + "\n" + upper + "\"\n"; result != expected {
+		t.Errorf("Normalize() of long records returned %d bytes; want %d", len(result), len(expected))
+	}
+}
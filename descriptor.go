@@ -0,0 +1,152 @@
+package textn8r
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unsafe"
+)
+
+// descriptor tells what a built-in normalizer does, so that Reader and Writer
+// know how to feed it, Compile and AppendNormalize how to run it, and
+// FormatNormalizers how to write it, without calling it. The normalizers
+// built from the registry carry the step that built them.
+//
+// The normalizers returned by constructors, such as ReplaceTabNormalizer or
+// WithScope, carry their descriptor: they are method values of it, from
+// which describe reads it back. The built-in normalizers that are named
+// functions are described in namedDescriptors.
+type descriptor struct {
+	normalize Normalizer
+	scope     Scope
+	// runs is the class of runes a ScopeStream normalizer replaces by runs,
+	// if any. The stream is never split inside a run.
+	runs func(rune) bool
+	// step is the step of the normalizers that work one rune at a time, when
+	// fusible is set. Compile fuses them and AppendNormalize runs them on
+	// bytes.
+	step    runeStep
+	fusible bool
+	// appendTo appends the output of other normalizers to dst without
+	// allocating, if set.
+	appendTo func(dst []byte, input string) []byte
+	// spec is the step of the pipeline syntax giving the normalizer back,
+	// when it has a name.
+	spec StepSpec
+}
+
+// apply runs the normalizer. Its method values are the normalizers carrying
+// a descriptor.
+func (d *descriptor) apply(input string) string {
+	return d.normalize(input)
+}
+
+// normalizer returns the normalizer described by d.
+func (d *descriptor) normalizer() Normalizer {
+	return d.apply
+}
+
+// methodValue is the layout of the method values of apply: the code of the
+// method wrapper, shared by all of them, followed by the receiver.
+type methodValue struct {
+	code     uintptr
+	receiver *descriptor
+}
+
+// describedCode is the code of the method values of apply.
+var describedCode = reflect.ValueOf((*descriptor)(nil).apply).Pointer()
+
+// namedDescriptors holds the descriptors of the built-in normalizers that are
+// named functions, by the address of their code. Unlike closures, which a
+// constructor inlined into its callers copies into each of them, a named
+// function has a single one.
+var namedDescriptors = map[uintptr]*descriptor{}
+
+// describe returns the descriptor of n, or nil for normalizers without one,
+// such as custom ones.
+func describe(n Normalizer) *descriptor {
+	if n == nil {
+		return nil
+	}
+	code := reflect.ValueOf(n).Pointer()
+	if code == describedCode {
+		return (*(**methodValue)(unsafe.Pointer(&n))).receiver
+	}
+
+	return namedDescriptors[code]
+}
+
+// withSpec returns n described as built by the step spec, keeping what its
+// own descriptor tells.
+func withSpec(n Normalizer, spec StepSpec) Normalizer {
+	d := &descriptor{normalize: n}
+	if described := describe(n); described != nil {
+		*d = *described
+	}
+	d.spec = spec
+
+	return d.normalizer()
+}
+
+func init() {
+	maps := func(mapping func(rune) rune) descriptor {
+		return descriptor{scope: ScopeStream, step: runeStep{kind: stepMap, mapping: mapping}, fusible: true}
+	}
+	removes := func(match func(rune) bool) descriptor {
+		return descriptor{scope: ScopeStream, step: runeStep{kind: stepReplace, match: match}, fusible: true}
+	}
+	kind := func(kind stepKind) descriptor {
+		return descriptor{scope: ScopeStream, step: runeStep{kind: kind}, fusible: true}
+	}
+	appends := func(scope Scope, appendTo func(dst []byte, input string) []byte) descriptor {
+		return descriptor{scope: scope, appendTo: appendTo}
+	}
+	plain := descriptor{}
+
+	for _, named := range []struct {
+		normalizer Normalizer
+		name       string
+		descriptor descriptor
+	}{
+		{UpperCaseNormalizer, "upper", maps(unicode.ToUpper)},
+		{LowerCaseNormalizer, "lower", maps(unicode.ToLower)},
+		{CaseFoldNormalizer, "case_fold", kind(stepCaseFold)},
+		{ReplaceAccentsNormalizer, "fold_accents", kind(stepFoldAccents)},
+		{ReplaceTildesNormalizer, "replace_tildes", descriptor{
+			scope: ScopeStream, step: runeStep{kind: stepReplace, match: isRune('ñ'), replacement: "n"}, fusible: true,
+		}},
+		{RemoveAllSpaceNormalizer, "remove_all_space", removes(isRune(' '))},
+		{RemoveCarriageReturnNormalizer, "remove_carriage_return", removes(isRune('\r'))},
+		{RemoveNewLineNormalizer, "remove_new_line", removes(isRune('\n'))},
+		{RemoveTabNormalizer, "remove_tab", removes(isRune('\t'))},
+		{RemoveTildesNormalizer, "remove_tildes", removes(isRune('~'))},
+		{RemoveNonAlphanumericNormalizer, "remove_non_alphanumeric", removes(isNotASCIIAlphanumeric)},
+		{RemoveDiacriticsNormalizer, "remove_diacritics", removes(isNotASCII)},
+		{RemovePunctuationNormalizer, "remove_punctuation", removes(isRemovedPunctuation)},
+		{RemoveDigitsNormalizer, "remove_digits", removes(isASCIIDigit)},
+		{RemoveSpecialCharactersNormalizer, "remove_special_characters", descriptor{
+			scope: ScopeStream, runs: isSpecialCharacter, fusible: true,
+			step: runeStep{kind: stepReplace, match: isSpecialCharacter, replacement: " ", runs: true},
+		}},
+		{NFCNormalizer, "nfc", appends(ScopeStream, FormNFC.appendString)},
+		{NFDNormalizer, "nfd", appends(ScopeStream, FormNFD.appendString)},
+		{NFKCNormalizer, "nfkc", appends(ScopeStream, FormNFKC.appendString)},
+		{NFKDNormalizer, "nfkd", appends(ScopeStream, FormNFKD.appendString)},
+		{TrimSpaceNormalizer, "trim", appends(ScopeDocument, func(dst []byte, input string) []byte {
+			return append(dst, strings.TrimSpace(input)...)
+		})},
+		{RemoveExtraSpaceNormalizer, "remove_extra_space", appends(ScopeDocument, appendFields)},
+		{CamelCaseNormalizer, "camel_case", plain},
+		{PascalCaseNormalizer, "pascal_case", plain},
+		{SnakeCaseNormalizer, "snake_case", plain},
+		{KebabCaseNormalizer, "kebab_case", plain},
+		{ScreamingSnakeCaseNormalizer, "screaming_snake_case", plain},
+		{DotCaseNormalizer, "dot_case", plain},
+		{TrainCaseNormalizer, "train_case", plain},
+	} {
+		d := named.descriptor
+		d.normalize = named.normalizer
+		d.spec = StepSpec{Name: named.name}
+		namedDescriptors[reflect.ValueOf(named.normalizer).Pointer()] = &d
+	}
+}
@@ -0,0 +1,43 @@
+package textn8r
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	tab, space := ReplaceTabNormalizer("-"), ReplaceSpaceNormalizer("_")
+	wrapped := func(input string) string { return tab(input) }
+
+	tests := []struct {
+		normalizer  Normalizer
+		described   bool
+		replacement string
+	}{
+		{tab, true, "-"},
+		{space, true, "_"},
+		{ReplaceTabNormalizer("+"), true, "+"},
+		{WithScope(tab, ScopeLine), true, "-"},
+		{WithScope(strings.ToUpper, ScopeStream), true, ""},
+		{UpperCaseNormalizer, true, ""},
+		{wrapped, false, ""},
+		{strings.TrimSpace, false, ""},
+		{nil, false, ""},
+	}
+
+	for i, tt := range tests {
+		d := describe(tt.normalizer)
+		if (d != nil) != tt.described {
+			t.Errorf("describe(tests[%d]) = %v; want a descriptor: %v", i, d, tt.described)
+			continue
+		}
+		if d != nil && d.step.replacement != tt.replacement {
+			t.Errorf("describe(tests[%d]) replacement = %q; want %q", i, d.step.replacement, tt.replacement)
+		}
+	}
+
+	// The descriptor runs the normalizer it describes.
+	if result := describe(tab).apply("a\tb"); result != "a-b" {
+		t.Errorf("describe(tab).apply() = %q; want a-b", result)
+	}
+}
@@ -0,0 +1,570 @@
+package textn8r
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// StepSpec names a registered normalizer and its arguments.
+type StepSpec struct {
+	Name string `json:"name"`
+	Args Args   `json:"args,omitempty"`
+}
+
+// String returns the step in the pipeline syntax, such as `replace_tab("-")`.
+// Arguments of registered normalizers are written by position, leaving out
+// trailing optional ones that have their default value.
+func (s StepSpec) String() string {
+	d, ok := Lookup(s.Name)
+	for name := range s.Args {
+		if ok && d.paramIndex(name) < 0 {
+			ok = false
+		}
+	}
+	if !ok {
+		return s.Name + formatNamedArgs(s.Args)
+	}
+
+	last := -1
+	for i, p := range d.Params {
+		if value, ok := s.Args[p.Name]; ok && (p.Required || formatValue(value) != formatValue(paramDefault(p))) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return s.Name
+	}
+
+	values := make([]string, last+1)
+	for i, p := range d.Params[:last+1] {
+		value, ok := s.Args[p.Name]
+		if !ok {
+			value = paramDefault(p)
+		}
+		values[i] = formatValue(value)
+	}
+
+	return s.Name + "(" + strings.Join(values, ", ") + ")"
+}
+
+func formatNamedArgs(args Args) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = name + "=" + formatValue(args[name])
+	}
+
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+func paramDefault(p Param) any {
+	if p.Default == nil {
+		return zeroValue(p.Type)
+	}
+
+	return p.Default
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = strconv.Quote(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	return "null"
+}
+
+// Spec is a pipeline of registered normalizers, as written in the pipeline
+// syntax.
+type Spec []StepSpec
+
+// String returns the pipeline in the pipeline syntax.
+func (s Spec) String() string {
+	steps := make([]string, len(s))
+	for i, step := range s {
+		steps[i] = step.String()
+	}
+
+	return strings.Join(steps, " | ")
+}
+
+// Normalizers builds the steps with BuildNormalizer.
+func (s Spec) Normalizers() (Normalizers, error) {
+	normalizers := make(Normalizers, 0, len(s))
+	for _, step := range s {
+		n, err := BuildNormalizer(step.Name, step.Args)
+		if err != nil {
+			return nil, err
+		}
+		normalizers = append(normalizers, n)
+	}
+
+	return normalizers, nil
+}
+
+// Pipeline builds the steps with Build into a Pipeline whose steps are named
+// after the normalizers.
+func (s Spec) Pipeline() (*Pipeline, error) {
+	p := NewPipeline()
+	for _, step := range s {
+		n, err := Build(step.Name, step.Args)
+		if err != nil {
+			return nil, err
+		}
+		p.AddE(step.Name, n)
+	}
+
+	return p, nil
+}
+
+// SyntaxError reports an invalid pipeline with the position of the problem.
+type SyntaxError struct {
+	// Line and Column start at 1. Columns count characters.
+	Line   int
+	Column int
+	Msg    string
+	// Err is the registry error behind the problem, if any.
+	Err error
+}
+
+func (e *SyntaxError) Error() string {
+	return "textn8r: line " + strconv.Itoa(e.Line) + ", column " + strconv.Itoa(e.Column) + ": " + e.Msg
+}
+
+// Unwrap returns the registry error behind the problem, if any.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// ParseSpec parses a pipeline such as
+//
+//	trim | lower | fold_accents | replace_space("-") | collapse("-")
+//
+// Steps are registered normalizer names separated by "|", with their arguments
+// in parentheses when they take any. Arguments are given by position, in the
+// order of the parameters, or by name as in slug(max_length=40), and are
+// double-quoted strings with Go escapes, integers, true, false, or lists of
+// strings such as ["NASA", "iPhone"]. White space, including new lines, is
+// ignored, and "#" starts a comment running to the end of the line. An empty
+// input gives an empty Spec.
+//
+// Names are resolved and arguments checked against the registry. Problems are
+// reported as a *SyntaxError.
+func ParseSpec(src string) (Spec, error) {
+	spec, _, err := parseSpec(src)
+	return spec, err
+}
+
+// ParseNormalizers parses a pipeline with ParseSpec and builds its steps with
+// BuildNormalizer.
+func ParseNormalizers(src string) (Normalizers, error) {
+	spec, positions, err := parseSpec(src)
+	if err != nil {
+		return nil, err
+	}
+
+	normalizers := make(Normalizers, 0, len(spec))
+	for i, step := range spec {
+		n, err := BuildNormalizer(step.Name, step.Args)
+		if err != nil {
+			return nil, positions[i].error(err)
+		}
+		normalizers = append(normalizers, n)
+	}
+
+	return normalizers, nil
+}
+
+// ParsePipeline parses a pipeline with ParseSpec and builds its steps with
+// Build, so it can hold normalizers that reject their input.
+func ParsePipeline(src string) (*Pipeline, error) {
+	spec, positions, err := parseSpec(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewPipeline()
+	for i, step := range spec {
+		n, err := Build(step.Name, step.Args)
+		if err != nil {
+			return nil, positions[i].error(err)
+		}
+		p.AddE(step.Name, n)
+	}
+
+	return p, nil
+}
+
+// FormatNormalizers returns the normalizers in the pipeline syntax, so that
+// ParseNormalizers gives them back. It knows the built-in normalizers that do
+// not take parameters and the normalizers built from the registry, such as
+// those of ParseNormalizers and Spec.Normalizers, with their arguments. The
+// parameters of normalizers returned by constructors, such as
+// ReplaceTabNormalizer(" "), cannot be read back, so they give a
+// *FormatError, as custom normalizers do.
+func FormatNormalizers(normalizers Normalizers) (string, error) {
+	spec, err := SpecOf(normalizers)
+	if err != nil {
+		return "", err
+	}
+
+	return spec.String(), nil
+}
+
+// FormatError reports a normalizer that FormatNormalizers cannot write in the
+// pipeline syntax.
+type FormatError struct {
+	// Index is the position of the normalizer.
+	Index int
+}
+
+func (e *FormatError) Error() string {
+	return "textn8r: normalizer #" + strconv.Itoa(e.Index) + " cannot be written in the pipeline syntax"
+}
+
+// position is a position in the pipeline syntax.
+type position struct {
+	line, column int
+}
+
+func (p position) errorf(msg string) *SyntaxError {
+	return &SyntaxError{Line: p.line, Column: p.column, Msg: msg}
+}
+
+// error wraps a registry error.
+func (p position) error(err error) *SyntaxError {
+	return &SyntaxError{Line: p.line, Column: p.column, Msg: strings.TrimPrefix(err.Error(), "textn8r: "), Err: err}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenString
+	tokenInt
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	// text is the source of the token, or the value of strings.
+	text string
+	pos  position
+}
+
+// describe returns how the token is named in errors.
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return "string " + strconv.Quote(t.text)
+	}
+
+	return strconv.Quote(t.text)
+}
+
+// scanner splits the pipeline syntax into tokens.
+type scanner struct {
+	src string
+	off int
+	pos position
+}
+
+func (s *scanner) advance(n int) {
+	for _, r := range s.src[s.off : s.off+n] {
+		if r == '\n' {
+			s.pos.line++
+			s.pos.column = 1
+		} else {
+			s.pos.column++
+		}
+	}
+	s.off += n
+}
+
+func (s *scanner) next() (token, error) {
+	for s.off < len(s.src) {
+		c := s.src[s.off]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			s.advance(1)
+			continue
+		case c == '#':
+			end := strings.IndexByte(s.src[s.off:], '\n')
+			if end < 0 {
+				end = len(s.src) - s.off
+			}
+			s.advance(end)
+			continue
+		}
+		break
+	}
+
+	start := s.pos
+	if s.off == len(s.src) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	rest := s.src[s.off:]
+	c := rest[0]
+	switch {
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		n := 1
+		for n < len(rest) && (rest[n] == '_' || isASCIIAlphanumeric(rest[n])) {
+			n++
+		}
+		s.advance(n)
+		return token{kind: tokenName, text: rest[:n], pos: start}, nil
+
+	case c == '-' || isASCIIDigit(rune(c)):
+		n := 1
+		for n < len(rest) && isASCIIDigit(rune(rest[n])) {
+			n++
+		}
+		if c == '-' && n == 1 {
+			return token{}, start.errorf(`want a digit after "-"`)
+		}
+		s.advance(n)
+		return token{kind: tokenInt, text: rest[:n], pos: start}, nil
+
+	case c == '"':
+		n := 1
+		for ; n < len(rest) && rest[n] != '"'; n++ {
+			if rest[n] == '\n' {
+				break
+			}
+			if rest[n] == '\\' {
+				n++
+			}
+		}
+		if n >= len(rest) || rest[n] != '"' {
+			return token{}, start.errorf("unterminated string")
+		}
+		value, err := strconv.Unquote(rest[:n+1])
+		if err != nil {
+			return token{}, start.errorf("invalid string " + rest[:n+1])
+		}
+		s.advance(n + 1)
+		return token{kind: tokenString, text: value, pos: start}, nil
+
+	case strings.IndexByte("|(),=[]", c) >= 0:
+		s.advance(1)
+		return token{kind: tokenPunct, text: rest[:1], pos: start}, nil
+	}
+
+	r, _ := utf8.DecodeRuneInString(rest)
+	return token{}, start.errorf("unexpected character " + strconv.QuoteRune(r))
+}
+
+// parser parses the pipeline syntax with one token of lookahead.
+type parser struct {
+	scanner scanner
+	tok     token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.scanner.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+
+	return nil
+}
+
+func (p *parser) is(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.text == punct
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.is(punct) {
+		return p.tok.pos.errorf("want " + strconv.Quote(punct) + ", got " + p.tok.describe())
+	}
+
+	return p.advance()
+}
+
+func parseSpec(src string) (Spec, []position, error) {
+	p := &parser{scanner: scanner{src: src, pos: position{line: 1, column: 1}}}
+	if err := p.advance(); err != nil {
+		return nil, nil, err
+	}
+	if p.tok.kind == tokenEOF {
+		return Spec{}, nil, nil
+	}
+
+	var spec Spec
+	var positions []position
+	for {
+		pos := p.tok.pos
+		step, err := p.step()
+		if err != nil {
+			return nil, nil, err
+		}
+		spec = append(spec, step)
+		positions = append(positions, pos)
+
+		if p.tok.kind == tokenEOF {
+			return spec, positions, nil
+		}
+		if err := p.expect("|"); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+func (p *parser) step() (StepSpec, error) {
+	if p.tok.kind != tokenName {
+		return StepSpec{}, p.tok.pos.errorf("want a normalizer name, got " + p.tok.describe())
+	}
+	name, pos := p.tok.text, p.tok.pos
+	d, ok := Lookup(name)
+	if !ok {
+		return StepSpec{}, pos.error(&UnknownNormalizerError{Name: name})
+	}
+	if err := p.advance(); err != nil {
+		return StepSpec{}, err
+	}
+
+	args := Args{}
+	argPositions := map[string]position{}
+	if p.is("(") {
+		if err := p.advance(); err != nil {
+			return StepSpec{}, err
+		}
+		named := false
+		for i := 0; !p.is(")"); i++ {
+			if i > 0 {
+				if err := p.expect(","); err != nil {
+					return StepSpec{}, err
+				}
+			}
+
+			argPos := p.tok.pos
+			var param string
+			if p.tok.kind == tokenName && p.tok.text != "true" && p.tok.text != "false" {
+				param = p.tok.text
+				if err := p.advance(); err != nil {
+					return StepSpec{}, err
+				}
+				if err := p.expect("="); err != nil {
+					return StepSpec{}, err
+				}
+				named = true
+			} else {
+				if named {
+					return StepSpec{}, argPos.errorf("positional argument after named ones")
+				}
+				if i >= len(d.Params) {
+					return StepSpec{}, argPos.errorf(name + " takes at most " + strconv.Itoa(len(d.Params)) + " arguments")
+				}
+				param = d.Params[i].Name
+			}
+			if _, ok := args[param]; ok {
+				return StepSpec{}, argPos.errorf("argument " + param + " given twice")
+			}
+
+			value, err := p.value()
+			if err != nil {
+				return StepSpec{}, err
+			}
+			args[param] = value
+			argPositions[param] = argPos
+		}
+		if err := p.advance(); err != nil {
+			return StepSpec{}, err
+		}
+	}
+
+	if _, err := d.check(args); err != nil {
+		if argErr, ok := err.(*ArgumentError); ok {
+			if argPos, ok := argPositions[argErr.Param]; ok {
+				pos = argPos
+			}
+		}
+		return StepSpec{}, pos.error(err)
+	}
+	if len(args) == 0 {
+		args = nil
+	}
+
+	return StepSpec{Name: name, Args: args}, nil
+}
+
+func (p *parser) value() (any, error) {
+	tok := p.tok
+	var value any
+	switch {
+	case tok.kind == tokenString:
+		value = tok.text
+	case tok.kind == tokenInt:
+		i, err := strconv.Atoi(tok.text)
+		if err != nil {
+			return nil, tok.pos.errorf("integer " + tok.text + " out of range")
+		}
+		value = i
+	case tok.kind == tokenName && (tok.text == "true" || tok.text == "false"):
+		value = tok.text == "true"
+	case p.is("["):
+		return p.list()
+	default:
+		return nil, tok.pos.errorf("want a value, got " + tok.describe())
+	}
+
+	return value, p.advance()
+}
+
+func (p *parser) list() (any, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	items := []string{}
+	for !p.is("]") {
+		if len(items) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		if p.tok.kind != tokenString {
+			return nil, p.tok.pos.errorf("want a string, got " + p.tok.describe())
+		}
+		items = append(items, p.tok.text)
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, p.advance()
+}
@@ -0,0 +1,258 @@
+package textn8r
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected Spec
+	}{
+		{"", Spec{}},
+		{"trim", Spec{{Name: "trim"}}},
+		{
+			`trim | lower | fold_accents | replace_space("-") | collapse("-")`,
+			Spec{
+				{Name: "trim"},
+				{Name: "lower"},
+				{Name: "fold_accents"},
+				{Name: "replace_space", Args: Args{"replacement": "-"}},
+				{Name: "collapse", Args: Args{"text": "-"}},
+			},
+		},
+		{
+			"slug(max_length=40, stop_words=[\"a\", \"the\"]) # comment\n| title_case()",
+			Spec{
+				{Name: "slug", Args: Args{"max_length": 40, "stop_words": []string{"a", "the"}}},
+				{Name: "title_case"},
+			},
+		},
+		{`slug("_", 12, [], "", true)`, Spec{{Name: "slug", Args: Args{
+			"separator": "_", "max_length": 12, "stop_words": []string{}, "locale": "", "keep_unicode": true,
+		}}}},
+		{`replace_tab("\té\"")`, Spec{{Name: "replace_tab", Args: Args{"replacement": "\té\""}}}},
+	}
+
+	for _, tt := range tests {
+		spec, err := ParseSpec(tt.src)
+		if err != nil {
+			t.Errorf("ParseSpec(%q) error = %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(spec, tt.expected) {
+			t.Errorf("ParseSpec(%q) = %#v; want %#v", tt.src, spec, tt.expected)
+		}
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		line    int
+		column  int
+		message string
+	}{
+		{"trim |", 1, 7, "want a normalizer name, got end of input"},
+		{"trim lower", 1, 6, `want "|", got "lower"`},
+		{"trim | lowr", 1, 8, `unknown normalizer "lowr"`},
+		{`trim | replace_tab(1)`, 1, 20, `normalizer "replace_tab": parameter "replacement": want string`},
+		{`trim | replace_tab`, 1, 8, `normalizer "replace_tab": parameter "replacement": missing required string`},
+		{`replace_tab("-", "+")`, 1, 18, "replace_tab takes at most 1 arguments"},
+		{`slug(max_length=1, "-")`, 1, 20, "positional argument after named ones"},
+		{`slug("-", separator="+")`, 1, 11, "argument separator given twice"},
+		{`replace_tab("-`, 1, 13, "unterminated string"},
+		{"trim |\n  lower |\n  ¿", 3, 3, "unexpected character '¿'"},
+		{`slug(max_length=)`, 1, 17, `want a value, got ")"`},
+		{`slug(stop_words=["a" "b"])`, 1, 22, `want ",", got string "b"`},
+		{`slug(max_length=-)`, 1, 17, `want a digit after "-"`},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseSpec(tt.src)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseSpec(%q) error = %v; want a *SyntaxError", tt.src, err)
+			continue
+		}
+		if syntaxErr.Line != tt.line || syntaxErr.Column != tt.column || syntaxErr.Msg != tt.message {
+			t.Errorf("ParseSpec(%q) error = %d:%d %q; want %d:%d %q",
+				tt.src, syntaxErr.Line, syntaxErr.Column, syntaxErr.Msg, tt.line, tt.column, tt.message)
+		}
+	}
+
+	_, err := ParseSpec("trim | lowr")
+	var unknown *UnknownNormalizerError
+	if !errors.As(err, &unknown) || unknown.Name != "lowr" {
+		t.Errorf("ParseSpec error = %v; want an *UnknownNormalizerError", err)
+	}
+	if err.Error() != `textn8r: line 1, column 8: unknown normalizer "lowr"` {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestParseNormalizers(t *testing.T) {
+	n, err := ParseNormalizers(`trim | lower | fold_accents | replace_space("-") | collapse("-")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input, expected := "  Crème  Brûlée - Recipe ", "creme-brulee-recipe"
+	if result := n.Apply(input); result != expected {
+		t.Errorf("Apply(%q) = %q; want %q", input, result, expected)
+	}
+}
+
+func TestParsePipeline(t *testing.T) {
+	p, err := ParsePipeline("trim | upper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Steps) != 2 || p.Steps[1].Name != "upper" {
+		t.Fatalf("ParsePipeline steps = %+v", p.Steps)
+	}
+	if result, err := p.Apply(" a "); result != "A" || err != nil {
+		t.Errorf("Apply(%q) = %q, %v; want %q, nil", " a ", result, err, "A")
+	}
+}
+
+func TestFormatNormalizers(t *testing.T) {
+	tests := []struct {
+		normalizers Normalizers
+		expected    string
+	}{
+		{Normalizers{}, ""},
+		{
+			Normalizers{TrimSpaceNormalizer, LowerCaseNormalizer, ReplaceAccentsNormalizer, RemoveDigitsNormalizer},
+			`trim | lower | fold_accents | remove_digits`,
+		},
+		{Normalizers{RemoveSpecialCharactersNormalizer, NFKCNormalizer}, `remove_special_characters | nfkc`},
+		{Normalizers{ScreamingSnakeCaseNormalizer}, "screaming_snake_case"},
+	}
+
+	for _, tt := range tests {
+		result, err := FormatNormalizers(tt.normalizers)
+		if err != nil {
+			t.Errorf("FormatNormalizers(%q) error = %v", tt.expected, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("FormatNormalizers() = %q; want %q", result, tt.expected)
+		}
+	}
+
+	custom := func(input string) string { return input }
+	_, err := FormatNormalizers(Normalizers{TrimSpaceNormalizer, custom})
+	var formatErr *FormatError
+	if !errors.As(err, &formatErr) || formatErr.Index != 1 {
+		t.Errorf("FormatNormalizers() of a custom normalizer error = %v; want a *FormatError for #1", err)
+	}
+	// Parameters cannot be read back from a normalizer returned by a constructor.
+	for _, n := range []Normalizer{CollapseNormalizer("-"), ReplaceTabNormalizer(" "), TransliterateToASCIINormalizer("?")} {
+		if _, err := FormatNormalizers(Normalizers{n}); !errors.As(err, &formatErr) {
+			t.Errorf("FormatNormalizers() of a normalizer with parameters error = %v; want a *FormatError", err)
+		}
+	}
+}
+
+func TestFormatNormalizersRoundTrip(t *testing.T) {
+	sources := []string{
+		`trim | lower | fold_accents | remove_digits | remove_extra_space`,
+		`snake_case | nfkc | case_fold`,
+		`pascal_case | replace_tildes | remove_all_space`,
+		`lower | replace_space("-") | collapse("-")`,
+		`slug("_", 40) | transliterate_ascii`,
+	}
+
+	for _, src := range sources {
+		n, err := ParseNormalizers(src)
+		if err != nil {
+			t.Errorf("ParseNormalizers(%q) error = %v", src, err)
+			continue
+		}
+		result, err := FormatNormalizers(n)
+		if err != nil {
+			t.Errorf("FormatNormalizers(%q) error = %v", src, err)
+			continue
+		}
+		if result != src {
+			t.Errorf("FormatNormalizers(ParseNormalizers(%q)) = %q", src, result)
+		}
+	}
+}
+
+func TestFormatSpecNormalizers(t *testing.T) {
+	spec, err := ParseSpec(`replace_space("-") | collapse("-")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := spec.Normalizers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := FormatNormalizers(n)
+	if err != nil {
+		t.Fatalf("FormatNormalizers() error = %v", err)
+	}
+	if result != spec.String() {
+		t.Errorf("FormatNormalizers() = %q; want %q", result, spec.String())
+	}
+	// Keeping the spec keeps replace_space fusible.
+	if _, ok := recognizeStep(n[0]); !ok {
+		t.Errorf("recognizeStep(replace_space) = false; want true")
+	}
+}
+
+func TestSpecStringRoundTrip(t *testing.T) {
+	sources := []string{
+		`trim | lower | fold_accents | replace_space("-") | collapse("-")`,
+		`lower_locale("tr") | title_case("chicago", ["NASA"])`,
+		`slug | slug("_") | slug("-", 40) | slug(keep_unicode=true)`,
+		`pascal_case(["ID", "URL"]) | transliterate("de") | transliterate_ascii`,
+		`replace_new_line("\n\n") | replace_diacritics("") | collapse(" ")`,
+		`replace_tab("\t->\"")`,
+	}
+	expected := []string{
+		sources[0],
+		sources[1],
+		`slug | slug("_") | slug("-", 40) | slug("-", 0, [], "", true)`,
+		sources[3],
+		sources[4],
+		sources[5],
+	}
+
+	for i, src := range sources {
+		spec, err := ParseSpec(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spec.String() != expected[i] {
+			t.Errorf("ParseSpec(%q).String() = %q; want %q", src, spec.String(), expected[i])
+		}
+		again, err := ParseSpec(spec.String())
+		if err != nil || again.String() != expected[i] {
+			t.Errorf("ParseSpec(%q) = %q, %v; want it unchanged", spec.String(), again.String(), err)
+		}
+	}
+}
+
+func TestStepSpecString(t *testing.T) {
+	tests := []struct {
+		step     StepSpec
+		expected string
+	}{
+		{StepSpec{Name: "trim"}, "trim"},
+		{StepSpec{Name: "slug", Args: Args{"separator": "-"}}, "slug"},
+		{StepSpec{Name: "slug", Args: Args{"max_length": float64(8)}}, `slug("-", 8)`},
+		{StepSpec{Name: "unregistered", Args: Args{"b": true, "a": []any{"x"}}}, `unregistered(a=["x"], b=true)`},
+		{StepSpec{Name: "trim", Args: Args{"cutset": " "}}, `trim(cutset=" ")`},
+	}
+
+	for _, tt := range tests {
+		if result := tt.step.String(); result != tt.expected {
+			t.Errorf("%+v.String() = %q; want %q", tt.step, result, tt.expected)
+		}
+	}
+}
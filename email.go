@@ -0,0 +1,154 @@
+package textn8r
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// EmailProvider holds the rules making the addresses of a mail provider
+// canonical, so the addresses delivered to the same mailbox compare equal.
+type EmailProvider struct {
+	// Domain replaces the domain of the addresses, such as "gmail.com" for
+	// "googlemail.com". Empty keeps it.
+	Domain string
+	// RemoveDots removes the dots of local parts, which the provider ignores.
+	RemoveDots bool
+	// TagSeparator starts the tag of subaddresses, such as "+" in
+	// "jane+news", which is removed. Empty keeps tags.
+	TagSeparator string
+	// LowerLocalPart lowercases local parts, which the provider compares
+	// regardless of case.
+	LowerLocalPart bool
+}
+
+// DefaultEmailProviders holds the rules of common mail providers by domain.
+var DefaultEmailProviders = map[string]EmailProvider{
+	"gmail.com":      {RemoveDots: true, TagSeparator: "+", LowerLocalPart: true},
+	"googlemail.com": {Domain: "gmail.com", RemoveDots: true, TagSeparator: "+", LowerLocalPart: true},
+	"outlook.com":    {TagSeparator: "+", LowerLocalPart: true},
+	"hotmail.com":    {TagSeparator: "+", LowerLocalPart: true},
+	"live.com":       {TagSeparator: "+", LowerLocalPart: true},
+	"icloud.com":     {TagSeparator: "+", LowerLocalPart: true},
+	"me.com":         {Domain: "icloud.com", TagSeparator: "+", LowerLocalPart: true},
+	"mac.com":        {Domain: "icloud.com", TagSeparator: "+", LowerLocalPart: true},
+	"fastmail.com":   {TagSeparator: "+", LowerLocalPart: true},
+	"proton.me":      {TagSeparator: "+", LowerLocalPart: true},
+	"protonmail.com": {Domain: "proton.me", TagSeparator: "+", LowerLocalPart: true},
+}
+
+// EmailOptions configures EmailNormalizer.
+type EmailOptions struct {
+	// Providers holds the rules of mail providers by domain, in ASCII form.
+	// Nil means no rules; DefaultEmailProviders holds the common ones.
+	Providers map[string]EmailProvider
+	// LowerLocalPart lowercases the local part of every address. Local parts
+	// are case-sensitive by RFC 5321, though few servers tell them apart.
+	LowerLocalPart bool
+}
+
+// EmailNormalizer returns a normalizer of email addresses. It parses the
+// input as an RFC 5322 address, which can have a display name, quoted local
+// parts and comments, and returns the bare address, with the domain
+// lowercased and in the ASCII form of IDNA, and the rules of its provider
+// applied:
+//
+//	n := EmailNormalizer(EmailOptions{Providers: DefaultEmailProviders})
+//	n(`Jane Doe <Jane.Doe+news@GoogleMail.com>`) // "janedoe@gmail.com"
+//
+// Local parts are quoted only when they need to be. Input that is not a
+// single address gives an *InvalidInputError.
+func EmailNormalizer(options EmailOptions) NormalizerE {
+	return func(input string) (string, error) {
+		invalid := func(reason string) (string, error) {
+			return input, &InvalidInputError{Input: input, Reason: reason}
+		}
+
+		address, err := mail.ParseAddress(input)
+		if err != nil {
+			return invalid("not an email address: " + strings.TrimPrefix(err.Error(), "mail: "))
+		}
+		at := strings.LastIndexByte(address.Address, '@')
+		local, domain := address.Address[:at], address.Address[at+1:]
+
+		// Domain literals, which hold IP addresses, are kept.
+		if !strings.HasPrefix(domain, "[") {
+			if domain, err = domainToASCII(domain); err != nil {
+				return invalid("invalid domain: " + err.Error())
+			}
+			if strings.HasSuffix(domain, ".") {
+				return invalid("invalid domain: final dot")
+			}
+		}
+
+		provider, ok := options.Providers[domain]
+		if options.LowerLocalPart || (ok && provider.LowerLocalPart) {
+			local = strings.ToLower(local)
+		}
+		if ok {
+			if provider.TagSeparator != "" {
+				if i := strings.Index(local, provider.TagSeparator); i > 0 {
+					local = local[:i]
+				}
+			}
+			if provider.RemoveDots {
+				local = strings.ReplaceAll(local, ".", "")
+			}
+			if provider.Domain != "" {
+				domain = provider.Domain
+			}
+			if local == "" {
+				return invalid("empty local part")
+			}
+		}
+
+		return quoteLocalPart(local) + "@" + domain, nil
+	}
+}
+
+// quoteLocalPart returns the local part as a dot-atom, or as a quoted string
+// when it cannot be one.
+func quoteLocalPart(local string) string {
+	if isDotAtom(local) {
+		return local
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(local) + 2)
+	sb.WriteByte('"')
+	for _, r := range local {
+		if r == '"' || r == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteByte('"')
+
+	return sb.String()
+}
+
+// isDotAtom reports whether s is a dot-atom of RFC 5322, with the UTF-8
+// characters of RFC 6532.
+func isDotAtom(s string) bool {
+	if s == "" || s[0] == '.' || s[len(s)-1] == '.' || strings.Contains(s, "..") {
+		return false
+	}
+	for _, r := range s {
+		if r != '.' && !isAtext(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isAtext reports whether r can be part of an atom of RFC 5322.
+func isAtext(r rune) bool {
+	if r >= 0x80 {
+		return true
+	}
+	if isASCIIAlphanumeric(byte(r)) {
+		return true
+	}
+
+	return strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
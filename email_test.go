@@ -0,0 +1,82 @@
+package textn8r
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEmailNormalizer(t *testing.T) {
+	providers := EmailNormalizer(EmailOptions{Providers: DefaultEmailProviders})
+	plain := EmailNormalizer(EmailOptions{})
+	lower := EmailNormalizer(EmailOptions{LowerLocalPart: true})
+	custom := EmailNormalizer(EmailOptions{Providers: map[string]EmailProvider{
+		"example.com": {TagSeparator: "--", Domain: "example.org"},
+	}})
+
+	tests := []struct {
+		name     string
+		n        NormalizerE
+		input    string
+		expected string
+	}{
+		{"domain lowercased", plain, "Jane.Doe@Example.COM", "Jane.Doe@example.com"},
+		{"local part lowercased", lower, "Jane.Doe@Example.COM", "jane.doe@example.com"},
+		{"display name", plain, `"Doe, Jane" <jane@example.com>`, "jane@example.com"},
+		{"comment", plain, "jane@example.com (Jane)", "jane@example.com"},
+		{"spaces", plain, "  <jane@example.com>  ", "jane@example.com"},
+		{"quoted local part", plain, `"jane doe"@example.com`, `"jane doe"@example.com`},
+		{"needless quotes", plain, `"jane"@example.com`, "jane@example.com"},
+		{"escaped quote", plain, `"a\"b"@example.com`, `"a\"b"@example.com`},
+		{"idna", plain, "jane@Bücher.Example", "jane@xn--bcher-kva.example"},
+		{"unicode local part", plain, "jöhn@example.com", "jöhn@example.com"},
+		{"domain literal", plain, "jane@[IPv6:::1]", "jane@[IPv6:::1]"},
+		{"gmail", providers, "Jane.Doe+News@GMail.com", "janedoe@gmail.com"},
+		{"googlemail", providers, "Jane Doe <j.a.n.e+x+y@googlemail.com>", "jane@gmail.com"},
+		{"outlook", providers, "Jane.Doe+x@Outlook.com", "jane.doe@outlook.com"},
+		{"leading separator kept", providers, "+jane@gmail.com", "+jane@gmail.com"},
+		{"no rules", providers, "Jane.Doe+x@example.com", "Jane.Doe+x@example.com"},
+		{"rules off", plain, "Jane.Doe+x@gmail.com", "Jane.Doe+x@gmail.com"},
+		{"custom", custom, "jane--list@Example.com", "jane@example.org"},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.n(tt.input)
+		if err != nil {
+			t.Errorf("%s: EmailNormalizer(%q) error = %v", tt.name, tt.input, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("%s: EmailNormalizer(%q) = %q; want %q", tt.name, tt.input, result, tt.expected)
+		}
+		if again, err := tt.n(result); err != nil || again != result {
+			t.Errorf("%s: EmailNormalizer(%q) = %q, %v; want it unchanged", tt.name, result, again, err)
+		}
+	}
+}
+
+func TestEmailNormalizerErrors(t *testing.T) {
+	n := EmailNormalizer(EmailOptions{Providers: DefaultEmailProviders})
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", `textn8r: invalid input "": not an email address: no address`},
+		{"jane", `textn8r: invalid input "jane": not an email address: missing '@' or angle-addr`},
+		{"a@b, c@d", `textn8r: invalid input "a@b, c@d": not an email address: expected single address, got ", c@d"`},
+		{"jane@-example.com", `textn8r: invalid input "jane@-example.com": invalid domain: label -example starts or ends with a hyphen`},
+		{"jane@ex_am!ple.com", `textn8r: invalid input "jane@ex_am!ple.com": invalid domain: invalid character '!' in label ex_am!ple`},
+		{"...+x@gmail.com", `textn8r: invalid input "...+x@gmail.com": not an email address: missing '@' or angle-addr`},
+		{`"."@gmail.com`, `textn8r: invalid input "\".\"@gmail.com": empty local part`},
+	}
+
+	for _, tt := range tests {
+		result, err := n(tt.input)
+		var invalid *InvalidInputError
+		if !errors.As(err, &invalid) || err.Error() != tt.expected {
+			t.Errorf("EmailNormalizer(%q) error = %v; want %s", tt.input, err, tt.expected)
+		}
+		if result != tt.input {
+			t.Errorf("EmailNormalizer(%q) = %q; want the input", tt.input, result)
+		}
+	}
+}
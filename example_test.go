@@ -1,7 +1,13 @@
 package textn8r_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
 
 	"github.com/p2p-b2b/textn8r"
@@ -98,3 +104,420 @@ func Example_urlSlug() {
 	// Output:
 	// how-to-create-amazing-web-apps-in-2023
 }
+
+// Example demonstrates Unicode normalization
+func ExampleNFCNormalizer() {
+	decomposed := "Cafe\u0301"
+
+	result := textn8r.NFCNormalizer(decomposed)
+	fmt.Println(result == "Café", textn8r.FormNFC.IsNormalized(decomposed))
+
+	// Output:
+	// true false
+}
+
+// Example demonstrates ASCII transliteration
+func ExampleTransliterateToASCIINormalizer() {
+	transliterator := textn8r.TransliterateToASCIINormalizer("?")
+
+	fmt.Println(transliterator.Apply("Москва, Αθήνα, 北京"))
+
+	// Output:
+	// Moskva, Athena, Bei Jing
+}
+
+// Example demonstrates title case with a style guide
+func ExampleTitleCaseNormalizer() {
+	titler := textn8r.TitleCaseNormalizer(textn8r.TitleCaseAP, "iOS")
+
+	fmt.Println(titler.Apply("how to create amazing web apps in 2023!"))
+	fmt.Println(titler.Apply("THE BEST IOS APPS OF THE YEAR"))
+
+	// Output:
+	// How to Create Amazing Web Apps in 2023!
+	// The Best iOS Apps of the Year
+}
+
+// Example demonstrates converting identifiers between cases
+func ExampleIdentifierCaseNormalizer() {
+	goName := textn8r.IdentifierCaseNormalizer(textn8r.CamelCase, textn8r.GoInitialisms...)
+
+	fmt.Println(textn8r.SnakeCaseNormalizer("getHTTPResponseCode"))
+	fmt.Println(goName.Apply("user_id_from_url"))
+
+	// Output:
+	// get_http_response_code
+	// userIDFromURL
+}
+
+// Example demonstrates generating unique slugs
+func ExampleSlugger() {
+	slugger := &textn8r.Slugger{
+		MaxLength: 40,
+		StopWords: []string{"a", "the"},
+		Store:     &textn8r.MemorySlugStore{},
+	}
+
+	fmt.Println(slugger.Slug("C++ & C#: The Definitive Guide"))
+	fmt.Println(slugger.Slug("C++ & C#: The Definitive Guide"))
+
+	// Output:
+	// c-plus-plus-and-c-sharp-definitive-guide
+	// c-plus-plus-and-c-sharp-definitive-2
+}
+
+// Example demonstrates compiling a pipeline into fused passes
+func ExampleCompile() {
+	normalize := textn8r.Compile(textn8r.Normalizers{
+		textn8r.TrimSpaceNormalizer,
+		textn8r.LowerCaseNormalizer,
+		textn8r.ReplaceAccentsNormalizer,
+		textn8r.RemoveDigitsNormalizer,
+	})
+
+	fmt.Printf("%q\n", normalize("  Crème Brûlée 42 "))
+
+	// Output:
+	// "creme brulee "
+}
+
+// Example demonstrates building and compiling a spec
+func ExampleSpec_Compile() {
+	spec, err := textn8r.ParseSpec(`trim | lower | fold_accents | replace_tab(" ") | remove_digits`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	normalize, err := spec.Compile()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("%q\n", normalize("  Crème\tBrûlée 42 "))
+
+	// Output:
+	// "creme brulee "
+}
+
+// Example demonstrates normalizing a stream line by line
+func ExampleNewReader() {
+	input := strings.NewReader("  Crème Brûlée  \n  Café au lait  \n")
+
+	r := textn8r.NewReader(input, textn8r.Normalizers{
+		textn8r.ReplaceAccentsNormalizer,
+		textn8r.UpperCaseNormalizer,
+		textn8r.WithScope(textn8r.TrimSpaceNormalizer, textn8r.ScopeLine),
+	})
+	if _, err := io.Copy(os.Stdout, r); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// CREME BRULEE
+	// CAFE AU LAIT
+}
+
+// Example demonstrates normalizing byte slices into a reused buffer
+func ExampleNormalizers_AppendNormalize() {
+	normalizers := textn8r.Normalizers{
+		textn8r.TrimSpaceNormalizer,
+		textn8r.ReplaceAccentsNormalizer,
+		textn8r.LowerCaseNormalizer,
+	}
+
+	var buf []byte
+	for _, line := range [][]byte{[]byte("  Crème Brûlée "), []byte("Café AU LAIT")} {
+		buf = normalizers.AppendNormalize(buf[:0], line)
+		fmt.Println(string(buf))
+	}
+
+	// Output:
+	// creme brulee
+	// cafe au lait
+}
+
+// Example demonstrates a pipeline with a step that rejects its input
+func ExamplePipeline() {
+	requireAt := func(input string) (string, error) {
+		if !strings.Contains(input, "@") {
+			return input, &textn8r.InvalidInputError{Input: input, Reason: `missing "@"`}
+		}
+		return input, nil
+	}
+
+	p := textn8r.NewPipeline().
+		Add("trim", textn8r.TrimSpaceNormalizer).
+		Add("lower", textn8r.LowerCaseNormalizer).
+		AddE("require_at", requireAt)
+
+	for _, input := range []string{"  Jane@Example.COM ", " jane.example.com"} {
+		result, err := p.Apply(input)
+
+		var stepErr *textn8r.StepError
+		if errors.As(err, &stepErr) {
+			fmt.Println("failed at", stepErr.Step+":", stepErr.Err)
+			continue
+		}
+		fmt.Println(result)
+	}
+
+	// Output:
+	// jane@example.com
+	// failed at require_at: textn8r: invalid input "jane.example.com": missing "@"
+}
+
+// Example demonstrates building normalizers by name from the registry
+func ExampleBuildNormalizer() {
+	d, _ := textn8r.Lookup("replace_tab")
+	fmt.Println(d.Name+":", d.Description)
+	for _, p := range d.Params {
+		fmt.Println(" ", p.Name, p.Type)
+	}
+
+	replaceTab, err := textn8r.BuildNormalizer("replace_tab", textn8r.Args{"replacement": " -> "})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(replaceTab("key\tvalue"))
+
+	_, err = textn8r.BuildNormalizer("replace_tabs", nil)
+	fmt.Println(err)
+
+	// Output:
+	// replace_tab: Replaces tabs
+	//   replacement string
+	// key -> value
+	// textn8r: unknown normalizer "replace_tabs"
+}
+
+// Example demonstrates parsing a pipeline and writing it back
+func ExampleParseNormalizers() {
+	n, err := textn8r.ParseNormalizers(`trim | lower | fold_accents | replace_space("-") | collapse("-")`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(n.Apply("  Crème  Brûlée Recipe "))
+
+	src, _ := textn8r.FormatNormalizers(textn8r.Normalizers{textn8r.TrimSpaceNormalizer, textn8r.RemoveTabNormalizer})
+	fmt.Println(src)
+
+	_, err = textn8r.ParseNormalizers(`trim | replace_space(-)`)
+	fmt.Println(err)
+
+	// Output:
+	// creme-brulee-recipe
+	// trim | remove_tab
+	// textn8r: line 1, column 22: want a digit after "-"
+}
+
+// Example demonstrates storing normalizers as JSON
+func ExampleNormalizers_UnmarshalJSON() {
+	var n textn8r.Normalizers
+	err := json.Unmarshal([]byte(`{"version": 1, "steps": [
+		{"name": "trim"},
+		{"name": "replace_space", "args": {"replacement": "_"}}
+	]}`), &n)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(n.Apply("  first name "))
+
+	data, _ := json.Marshal(textn8r.Normalizers{textn8r.TrimSpaceNormalizer, textn8r.LowerCaseNormalizer})
+	fmt.Println(string(data))
+
+	err = json.Unmarshal([]byte(`[{"name": "trim"}, {"name": "lowercase"}]`), &n)
+	fmt.Println(err)
+
+	// Output:
+	// first_name
+	// {"version":1,"steps":[{"name":"trim"},{"name":"lower"}]}
+	// textn8r: invalid pipeline: steps[1].name: unknown normalizer "lowercase"
+}
+
+// Example demonstrates normalizing columns of a CSV document
+func ExampleCSVNormalizer() {
+	c := &textn8r.CSVNormalizer{
+		Header: true,
+		Columns: []textn8r.CSVColumn{
+			{Header: "email", Normalizer: textn8r.Normalizers{textn8r.TrimSpaceNormalizer, textn8r.LowerCaseNormalizer}.NormalizerE()},
+		},
+	}
+
+	input := "name,email\n\"Doe, Jane\", Jane@Example.COM \n\"Roe, Rick\",rick@example.com\n"
+	stats, err := c.Normalize(os.Stdout, strings.NewReader(input))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, column := range stats.Columns {
+		fmt.Printf("%s: %d of %d changed\n", column.Column, column.Changed, stats.Records)
+	}
+
+	// Output:
+	// name,email
+	// "Doe, Jane",jane@example.com
+	// "Roe, Rick",rick@example.com
+	// email: 1 of 2 changed
+}
+
+// Example demonstrates normalizing strings inside JSON documents
+func ExampleJSONNormalizer() {
+	j := &textn8r.JSONNormalizer{
+		Rules: []textn8r.JSONRule{
+			{Selector: "$.users[*].email", Normalizer: textn8r.Normalizers{textn8r.TrimSpaceNormalizer, textn8r.LowerCaseNormalizer}.NormalizerE()},
+			{Selector: "*_name", Normalizer: textn8r.Normalizer(textn8r.TrimSpaceNormalizer).NormalizerE()},
+		},
+	}
+
+	input := `{"users": [{"first_name": " Jane ", "email": "Jane@Example.COM", "balance": 1234.5000}]}`
+	if _, err := j.Normalize(os.Stdout, strings.NewReader(input)); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {"users":[{"first_name":"Jane","email":"jane@example.com","balance":1234.5000}]}
+}
+
+// Example demonstrates normalizing struct fields from their tags
+func ExampleStruct() {
+	if err := textn8r.RegisterPipeline("user_email", "trim | lower"); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	type Address struct {
+		City string `normalize:"trim,title_case"`
+	}
+	type User struct {
+		Name    string   `normalize:"trim,collapse(\" \")"`
+		Email   *string  `normalize:"user_email"`
+		Tags    []string `normalize:"slug"`
+		Address *Address
+	}
+
+	email := " Jane@Example.COM "
+	u := User{Name: "  Jane   Doe ", Email: &email, Tags: []string{"Go Lang"}, Address: &Address{City: " new york"}}
+	if err := textn8r.Struct(&u); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%q %q %q %q\n", u.Name, *u.Email, u.Tags, u.Address.City)
+
+	// Output:
+	// "Jane Doe" "jane@example.com" ["go-lang"] "New York"
+}
+
+// Example demonstrates types normalizing themselves when decoded
+func ExampleNormalized() {
+	var user struct {
+		Email textn8r.EmailString `json:"email"`
+		Slug  textn8r.SlugString  `json:"slug"`
+	}
+
+	input := `{"email": " Jane@Example.COM ", "slug": "Crème Brûlée Recipe"}`
+	if err := json.Unmarshal([]byte(input), &user); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(user.Email, user.Slug)
+
+	err := json.Unmarshal([]byte(`{"email": "nobody"}`), &user)
+	fmt.Println(err)
+
+	// Output:
+	// Jane@example.com creme-brulee-recipe
+	// textn8r: invalid input "nobody": not an email address: missing '@' or angle-addr
+}
+
+// Example demonstrates normalizing request parameters in a middleware
+func ExampleHTTPNormalizer() {
+	h := &textn8r.HTTPNormalizer{
+		Query: []textn8r.HTTPRule{
+			{Name: "email", Normalizer: textn8r.Normalizers{textn8r.TrimSpaceNormalizer, textn8r.LowerCaseNormalizer}.NormalizerE()},
+			{Name: "*_id", Normalizer: textn8r.Normalizer(textn8r.RemoveAllSpaceNormalizer).NormalizerE()},
+		},
+	}
+	handler := h.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println(r.FormValue("email"), r.FormValue("user_id"))
+	}))
+
+	r := httptest.NewRequest("GET", "/users?email=+Jane@Example.COM+&user_id=12+34", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Output:
+	// jane@example.com 1234
+}
+
+// Example demonstrates making email addresses canonical
+func ExampleEmailNormalizer() {
+	n := textn8r.EmailNormalizer(textn8r.EmailOptions{Providers: textn8r.DefaultEmailProviders})
+
+	for _, input := range []string{
+		"Jane Doe <Jane.Doe+news@GoogleMail.com>",
+		`"jane doe"@Bücher.Example`,
+		"jane.doe@",
+	} {
+		email, err := n(input)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(email)
+	}
+
+	// Output:
+	// janedoe@gmail.com
+	// "jane doe"@xn--bcher-kva.example
+	// textn8r: invalid input "jane.doe@": not an email address: missing '@' or angle-addr
+}
+
+// Example demonstrates writing phone numbers in E.164 format
+func ExamplePhoneNormalizer() {
+	n := textn8r.PhoneNormalizer("US")
+
+	for _, input := range []string{"(555) 123-4567", "+34 612 34 56 78", "011 44 20 7946 0958 ext. 12", "555-1234"} {
+		phone, err := n(input)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(phone)
+	}
+
+	p, _ := textn8r.ParsePhone("0034612345678", "ES")
+	fmt.Println(p.Format(textn8r.PhoneInternational))
+
+	// Output:
+	// +15551234567
+	// +34612345678
+	// +442079460958;ext=12
+	// textn8r: invalid input "555-1234": too short for +1
+	// +34 612 34 56 78
+}
+
+// Example demonstrates making URLs canonical
+func ExampleURLNormalizer() {
+	n := textn8r.URLNormalizer(textn8r.DefaultURLOptions)
+
+	for _, input := range []string{
+		"HTTPS://Example.COM:443/a/./b/../c?utm_source=x&b=2&a=%7e1#top",
+		"http://Bücher.Example/Über uns?fbclid=abc",
+		"example.com/a",
+	} {
+		u, err := n(input)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(u)
+	}
+
+	// Output:
+	// https://example.com/a/c?a=~1&b=2
+	// http://xn--bcher-kva.example/%C3%9Cber%20uns
+	// textn8r: invalid input "example.com/a": not an absolute URL
+}
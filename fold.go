@@ -0,0 +1,123 @@
+package textn8r
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// foldTable maps letters that carry a diacritic but have no Unicode
+// decomposition, such as a stroke or a bar, to their base letter.
+var foldTable = map[rune]string{
+	'ł': "l", 'Ł': "L",
+	'đ': "d", 'Đ': "D",
+	'ø': "o", 'Ø': "O",
+	'ħ': "h", 'Ħ': "H",
+	'ŧ': "t", 'Ŧ': "T",
+	'ı': "i", 'ȷ': "j",
+	'ƀ': "b", 'Ƀ': "B",
+	'ƈ': "c", 'Ƈ': "C",
+	'ȼ': "c", 'Ȼ': "C",
+	'ɗ': "d", 'Ɗ': "D",
+	'ɇ': "e", 'Ɇ': "E",
+	'ƒ': "f", 'Ƒ': "F",
+	'ǥ': "g", 'Ǥ': "G",
+	'ɠ': "g", 'Ɠ': "G",
+	'ɨ': "i", 'Ɨ': "I",
+	'ɉ': "j", 'Ɉ': "J",
+	'ƙ': "k", 'Ƙ': "K",
+	'ƚ': "l", 'Ƚ': "L",
+	'ɲ': "n", 'Ɲ': "N",
+	'ƥ': "p", 'Ƥ': "P",
+	'ɍ': "r", 'Ɍ': "R",
+	'ƭ': "t", 'Ƭ': "T",
+	'ʈ': "t", 'Ʈ': "T",
+	'ʉ': "u", 'Ʉ': "U",
+	'ƴ': "y", 'Ƴ': "Y",
+	'ɏ': "y", 'Ɏ': "Y",
+	'ƶ': "z", 'Ƶ': "Z",
+	'ȥ': "z", 'Ȥ': "Z",
+	'ғ': "г", 'Ғ': "Г",
+	'ҟ': "к", 'Ҟ': "К",
+	'ҥ': "н", 'Ҥ': "Н",
+	'ұ': "ү", 'Ұ': "Ү",
+}
+
+// isFoldableScript reports whether diacritics are stripped from letters of
+// the script r belongs to.
+func isFoldableScript(r rune) bool {
+	if r < 0x80 {
+		return unicode.IsLetter(r)
+	}
+
+	return unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic)
+}
+
+// foldAccents removes the combining marks attached to Latin, Greek and
+// Cyrillic letters. Marks attached to any other script are kept, so
+// Devanagari vowel signs or Hebrew points survive the fold.
+func foldAccents(input string) string {
+	i := 0
+	for i < len(input) && input[i] < utf8.RuneSelf {
+		i++
+	}
+	if i == len(input) {
+		return input
+	}
+
+	// The output is only built once a rune changes, from the unchanged
+	// prefix input[:same].
+	var b strings.Builder
+	same := -1
+	changed := func(start int) {
+		if same < 0 {
+			same = start
+			b.Grow(len(input))
+			b.WriteString(input[:same])
+		}
+	}
+
+	// fold tells whether combining marks following the last base are dropped.
+	fold := i > 0 && isFoldableScript(rune(input[i-1]))
+	for i < len(input) {
+		start := i
+		r, size := utf8.DecodeRuneInString(input[i:])
+		raw := input[i : i+size]
+		i += size
+
+		if unicode.Is(unicode.Mn, r) {
+			if fold {
+				changed(start)
+			} else if same >= 0 {
+				b.WriteString(raw)
+			}
+			continue
+		}
+
+		fold = isFoldableScript(r)
+		if fold {
+			d, decomposed := canonicalDecompositionTable[r]
+			if decomposed {
+				r, _ = utf8.DecodeRuneInString(d)
+			}
+			if s, ok := foldTable[r]; ok {
+				changed(start)
+				b.WriteString(s)
+				continue
+			}
+			if decomposed {
+				changed(start)
+				b.WriteRune(r)
+				continue
+			}
+		}
+		if same >= 0 {
+			b.WriteString(raw)
+		}
+	}
+	if same < 0 {
+		return input
+	}
+
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package textn8r
+
+import (
+	"testing"
+)
+
+func TestReplaceAccentsNormalizerLatin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Łódź, Kraków", "Lodz, Krakow"},
+		{"Erdős Pál, Győr", "Erdos Pal, Gyor"},
+		{"București, Timișoara, Constanța", "Bucuresti, Timisoara, Constanta"},
+		{"Dvořák, Plzeň, Ústí nad Labem", "Dvorak, Plzen, Usti nad Labem"},
+		{"Ağrı, İstanbul, Iğdır", "Agri, Istanbul, Igdir"},
+		{"L'Haÿ-les-Roses, Cœur", "L'Hay-les-Roses, Cœur"},
+		{"Tiếng Việt, Hà Nội, Đà Nẵng", "Tieng Viet, Ha Noi, Da Nang"},
+		{"Øresund, Dubrovnik đak", "Oresund, Dubrovnik dak"},
+		{"ħaġar, Ħamrun", "hagar, Hamrun"},
+		{"Ångström", "Angstrom"},
+		{"café naïve", "cafe naive"},
+		{"hello world", "hello world"},
+	}
+
+	for _, tt := range tests {
+		result := ReplaceAccentsNormalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("ReplaceAccentsNormalizer(%s) = %s; want %s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestReplaceAccentsNormalizerGreek(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Αθήνα", "Αθηνα"},
+		{"Ελλάδα, Πελοπόννησος", "Ελλαδα, Πελοποννησος"},
+		{"ΐ ΰ ώ", "ι υ ω"},
+		{"ἄνθρωπος", "ανθρωπος"},
+	}
+
+	for _, tt := range tests {
+		result := ReplaceAccentsNormalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("ReplaceAccentsNormalizer(%s) = %s; want %s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestReplaceAccentsNormalizerCyrillic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Москва", "Москва"},
+		{"Йошкар-Ола, ёлка", "Иошкар-Ола, елка"},
+		{"Київ, Ґрунт", "Киів, Ґрунт"},
+		{"Ғазна", "Газна"},
+		{"Бе́лый", "Белыи"},
+	}
+
+	for _, tt := range tests {
+		result := ReplaceAccentsNormalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("ReplaceAccentsNormalizer(%s) = %s; want %s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestReplaceAccentsNormalizerOtherScripts(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"हिंदी", "हिंदी"},
+		{"שָׁלוֹם", "שָׁלוֹם"},
+		{"がぎぐ", "がぎぐ"},
+		{"ká゙", "ka"},
+		{"́abc", "́abc"},
+		{"caf\xe9 \xff", "caf\xe9 \xff"},
+	}
+
+	for _, tt := range tests {
+		result := ReplaceAccentsNormalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("ReplaceAccentsNormalizer(%+q) = %+q; want %+q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestReplaceAccentsNormalizerUnchanged(t *testing.T) {
+	inputs := []string{
+		"привет мир",
+		"ελληνικη γλωσσα",
+		"हिंदी שָׁלוֹם がぎぐ",
+		"caf\xe9 \xff",
+	}
+
+	for _, input := range inputs {
+		if result := ReplaceAccentsNormalizer(input); result != input {
+			t.Errorf("ReplaceAccentsNormalizer(%+q) = %+q; want it unchanged", input, result)
+			continue
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = ReplaceAccentsNormalizer(input)
+		})
+		if allocs != 0 {
+			t.Errorf("ReplaceAccentsNormalizer(%+q) allocated %v times; want 0", input, allocs)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package textn8r
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// HTTPRule applies a normalizer to the request parameters whose names match
+// Name, a name or a glob in the syntax of path.Match such as "*_id". Header
+// names match regardless of case.
+type HTTPRule struct {
+	Name       string
+	Normalizer NormalizerE
+}
+
+// HTTPNormalizer is a middleware normalizing the parameters of requests
+// before they reach the handler. Every rule matching a parameter applies to
+// each of its values, in order.
+type HTTPNormalizer struct {
+	// Query applies to the parameters of the URL query. The changed values
+	// are re-encoded in r.URL.RawQuery, keeping the order and the encoding
+	// of the other parameters, so r.URL.Query() and r.FormValue see the
+	// normalized values.
+	Query []HTTPRule
+	// Form applies to the fields of URL-encoded and multipart form bodies,
+	// in r.PostForm, r.Form and r.MultipartForm. Bodies are parsed before the
+	// handler runs, multipart ones with up to MaxMemory bytes in memory.
+	Form []HTTPRule
+	// Header applies to the request headers.
+	Header []HTTPRule
+	// MaxMemory is the maxMemory of ParseMultipartForm. Zero means 32 MB,
+	// as for r.FormValue.
+	MaxMemory int64
+	// Reject responds to requests holding a value a normalizer rejects,
+	// instead of leaving the value unchanged.
+	Reject bool
+	// ErrorHandler responds to rejected requests and to requests whose form
+	// cannot be parsed. Nil means a 400 Bad Request with the error message.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HTTPError reports a request parameter a normalizer rejected, or a form
+// that cannot be parsed.
+type HTTPError struct {
+	// Source is "query", "form" or "header".
+	Source string
+	// Name is the name of the parameter, empty for form parse errors.
+	Name string
+	Err  error
+}
+
+func (e *HTTPError) Error() string {
+	if e.Name == "" {
+		return "textn8r: http: " + e.Source + ": " + e.Err.Error()
+	}
+
+	return "textn8r: http: " + e.Source + " " + strconv.Quote(e.Name) + ": " + strings.TrimPrefix(e.Err.Error(), "textn8r: ")
+}
+
+// Unwrap returns the error of the normalizer or of the parser.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// defaultMaxMemory is the maxMemory of r.FormValue.
+const defaultMaxMemory = 32 << 20
+
+// Handler returns next wrapped by the middleware. next gets a clone of the
+// request, so the request of the caller is left unchanged, but the body is
+// shared and read when the form is parsed. Handler panics when a rule name
+// is an invalid glob, as http.ServeMux does for invalid patterns.
+func (h *HTTPNormalizer) Handler(next http.Handler) http.Handler {
+	for _, rules := range [][]HTTPRule{h.Query, h.Form, h.Header} {
+		for _, rule := range rules {
+			if _, err := path.Match(rule.Name, ""); err != nil || rule.Name == "" {
+				panic("textn8r: invalid HTTPRule name " + strconv.Quote(rule.Name))
+			}
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.Clone(r.Context())
+		if err := h.normalize(r); err != nil {
+			if h.ErrorHandler != nil {
+				h.ErrorHandler(w, r, err)
+			} else {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// normalize rewrites the parameters of the request.
+func (h *HTTPNormalizer) normalize(r *http.Request) error {
+	if len(h.Query) > 0 && r.URL.RawQuery != "" {
+		query := r.URL.Query()
+		changed, err := h.apply("query", h.Query, query, false)
+		if err != nil {
+			return err
+		}
+		if changed {
+			r.URL.RawQuery = encodeQuery(r.URL.RawQuery, query)
+			// The form holds the query when it was parsed already.
+			r.Form = nil
+		}
+	}
+
+	if len(h.Form) > 0 {
+		if err := h.normalizeForm(r); err != nil {
+			return err
+		}
+	}
+
+	if len(h.Header) > 0 {
+		if _, err := h.apply("header", h.Header, r.Header, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeQuery re-encodes the pairs of rawQuery whose values differ in query,
+// the parsed and normalized rawQuery. The other pairs, including those
+// url.ParseQuery skips, are kept as they are.
+func encodeQuery(rawQuery string, query url.Values) string {
+	pairs := strings.Split(rawQuery, "&")
+	seen := make(map[string]int)
+	for i, pair := range pairs {
+		if pair == "" || strings.Contains(pair, ";") {
+			continue
+		}
+		rawName, rawValue, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			continue
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			continue
+		}
+
+		n := seen[name]
+		seen[name]++
+		if values := query[name]; n < len(values) && values[n] != value {
+			pairs[i] = rawName + "=" + url.QueryEscape(values[n])
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// normalizeForm parses the form and normalizes the fields of the body. The
+// values of r.Form come from r.PostForm then from the query, which are
+// normalized already.
+func (h *HTTPNormalizer) normalizeForm(r *http.Request) error {
+	var err error
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		maxMemory := h.MaxMemory
+		if maxMemory == 0 {
+			maxMemory = defaultMaxMemory
+		}
+		err = r.ParseMultipartForm(maxMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		return &HTTPError{Source: "form", Err: err}
+	}
+
+	changed, err := h.apply("form", h.Form, r.PostForm, false)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	if r.MultipartForm != nil {
+		for name := range r.MultipartForm.Value {
+			r.MultipartForm.Value[name] = append([]string(nil), r.PostForm[name]...)
+		}
+	}
+	query := r.URL.Query()
+	for name, values := range r.PostForm {
+		r.Form[name] = append(append([]string(nil), values...), query[name]...)
+	}
+
+	return nil
+}
+
+// apply normalizes the values in place and reports whether one changed. It
+// returns an *HTTPError for rejected values in Reject mode.
+func (h *HTTPNormalizer) apply(source string, rules []HTTPRule, values map[string][]string, fold bool) (bool, error) {
+	changed := false
+	for name, list := range values {
+		match := name
+		if fold {
+			match = strings.ToLower(name)
+		}
+		for _, rule := range rules {
+			pattern := rule.Name
+			if fold {
+				pattern = strings.ToLower(pattern)
+			}
+			if ok, _ := path.Match(pattern, match); !ok {
+				continue
+			}
+
+			for i, value := range list {
+				output, err := rule.Normalizer(value)
+				if err != nil {
+					if h.Reject {
+						return changed, &HTTPError{Source: source, Name: name, Err: err}
+					}
+					continue
+				}
+				if output != value {
+					list[i] = output
+					changed = true
+				}
+			}
+		}
+	}
+
+	return changed, nil
+}
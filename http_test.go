@@ -0,0 +1,168 @@
+package textn8r
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// serveHTTP runs the request through the middleware and a handler recording
+// what it sees.
+func serveHTTP(t *testing.T, h *HTTPNormalizer, r *http.Request) (*httptest.ResponseRecorder, *http.Request) {
+	t.Helper()
+	var seen *http.Request
+	handler := h.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r
+		io.WriteString(w, "ok")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w, seen
+}
+
+func TestHTTPNormalizer(t *testing.T) {
+	lower := Normalizers{TrimSpaceNormalizer, LowerCaseNormalizer}.NormalizerE()
+	h := &HTTPNormalizer{
+		Query:  []HTTPRule{{"email", lower}, {"*_id", Normalizer(RemoveAllSpaceNormalizer).NormalizerE()}},
+		Form:   []HTTPRule{{"name", Normalizer(RemoveExtraSpaceNormalizer).NormalizerE()}, {"email", lower}},
+		Header: []HTTPRule{{"x-tenant-*", Normalizer(UpperCaseNormalizer).NormalizerE()}},
+	}
+
+	body := url.Values{"name": {"  Jane   Doe "}, "email": {" Jane@X.COM "}, "other": {" kept "}}
+	r := httptest.NewRequest("POST", "/users?email=+A@B.C+&user_id=1+2&email=X@Y&q=+Keep+", strings.NewReader(body.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Tenant-Name", "acme")
+	r.Header.Set("X-Other", "acme")
+
+	w, seen := serveHTTP(t, h, r)
+	if w.Code != http.StatusOK || seen == nil {
+		t.Fatalf("status = %d; want 200", w.Code)
+	}
+
+	query := seen.URL.Query()
+	if got := query["email"]; len(got) != 2 || got[0] != "a@b.c" || got[1] != "x@y" {
+		t.Errorf("query email = %q; want [a@b.c x@y]", got)
+	}
+	if got := query.Get("user_id"); got != "12" {
+		t.Errorf("query user_id = %q; want 12", got)
+	}
+	if got := query.Get("q"); got != " Keep " {
+		t.Errorf("query q = %q; want it unchanged", got)
+	}
+
+	if got := seen.PostForm.Get("name"); got != "Jane Doe" {
+		t.Errorf("PostForm name = %q; want Jane Doe", got)
+	}
+	if got := seen.PostForm.Get("other"); got != " kept " {
+		t.Errorf("PostForm other = %q; want it unchanged", got)
+	}
+	if got := seen.Form["email"]; len(got) != 3 || got[0] != "jane@x.com" || got[1] != "a@b.c" {
+		t.Errorf("Form email = %q; want the body value then the query ones", got)
+	}
+	if got := seen.FormValue("user_id"); got != "12" {
+		t.Errorf("FormValue(user_id) = %q; want 12", got)
+	}
+
+	if got := seen.Header.Get("X-Tenant-Name"); got != "ACME" {
+		t.Errorf("header X-Tenant-Name = %q; want ACME", got)
+	}
+	if got := seen.Header.Get("X-Other"); got != "acme" {
+		t.Errorf("header X-Other = %q; want it unchanged", got)
+	}
+
+	// The handler gets a clone, so the request of the caller is unchanged.
+	if r.URL.RawQuery != "email=+A@B.C+&user_id=1+2&email=X@Y&q=+Keep+" || r.Header.Get("X-Tenant-Name") != "acme" || r.PostForm != nil {
+		t.Errorf("request of the caller = %q %v %v; want it unchanged", r.URL.RawQuery, r.Header, r.PostForm)
+	}
+}
+
+func TestHTTPNormalizerQueryOrder(t *testing.T) {
+	h := &HTTPNormalizer{Query: []HTTPRule{{"b", Normalizer(UpperCaseNormalizer).NormalizerE()}}}
+	r := httptest.NewRequest("GET", "/?z=%7e&b=x&a&b=Y&bad=%zz&b=z+1", nil)
+
+	_, seen := serveHTTP(t, h, r)
+	if expected := "z=%7e&b=X&a&b=Y&bad=%zz&b=Z+1"; seen.URL.RawQuery != expected {
+		t.Errorf("RawQuery = %q; want %q", seen.URL.RawQuery, expected)
+	}
+}
+
+func TestHTTPNormalizerMultipart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("email", " A@B ")
+	mw.Close()
+
+	h := &HTTPNormalizer{Form: []HTTPRule{{"email", Normalizers{TrimSpaceNormalizer, LowerCaseNormalizer}.NormalizerE()}}}
+	r := httptest.NewRequest("POST", "/", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	w, seen := serveHTTP(t, h, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; want 200", w.Code)
+	}
+	if got := seen.FormValue("email"); got != "a@b" {
+		t.Errorf("FormValue(email) = %q; want a@b", got)
+	}
+	if got := seen.MultipartForm.Value["email"]; len(got) != 1 || got[0] != "a@b" {
+		t.Errorf("MultipartForm email = %q; want [a@b]", got)
+	}
+}
+
+func TestHTTPNormalizerReject(t *testing.T) {
+	h := &HTTPNormalizer{Query: []HTTPRule{{"email", requireAt}}}
+
+	// Rejected values are left unchanged without Reject.
+	w, seen := serveHTTP(t, h, httptest.NewRequest("GET", "/?email=nobody", nil))
+	if w.Code != http.StatusOK || seen.URL.Query().Get("email") != "nobody" {
+		t.Errorf("status = %d; want 200 with the value unchanged", w.Code)
+	}
+
+	h.Reject = true
+	w, seen = serveHTTP(t, h, httptest.NewRequest("GET", "/?email=nobody", nil))
+	if w.Code != http.StatusBadRequest || seen != nil {
+		t.Errorf("status = %d; want 400 without calling the handler", w.Code)
+	}
+	if expected := `textn8r: http: query "email": invalid input "nobody": missing "@"` + "\n"; w.Body.String() != expected {
+		t.Errorf("body = %q; want %q", w.Body.String(), expected)
+	}
+
+	var handled error
+	h.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	w, _ = serveHTTP(t, h, httptest.NewRequest("GET", "/?email=nobody", nil))
+	var httpErr *HTTPError
+	var invalid *InvalidInputError
+	if w.Code != http.StatusUnprocessableEntity || !errors.As(handled, &httpErr) || !errors.As(handled, &invalid) {
+		t.Errorf("ErrorHandler got %v with status %d", handled, w.Code)
+	}
+	if httpErr.Source != "query" || httpErr.Name != "email" {
+		t.Errorf("HTTPError = %+v; want query email", httpErr)
+	}
+
+	h = &HTTPNormalizer{Form: []HTTPRule{{"email", requireAt}}}
+	r := httptest.NewRequest("POST", "/", strings.NewReader("email=%zz"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if w, _ := serveHTTP(t, h, r); w.Code != http.StatusBadRequest {
+		t.Errorf("status of an invalid form = %d; want 400", w.Code)
+	}
+}
+
+func TestHTTPNormalizerInvalidGlob(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Handler() with an invalid glob did not panic")
+		}
+	}()
+
+	h := &HTTPNormalizer{Header: []HTTPRule{{"x-[", requireAt}}}
+	h.Handler(http.NotFoundHandler())
+}
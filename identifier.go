@@ -0,0 +1,207 @@
+package textn8r
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// IdentifierCase selects how IdentifierCaseNormalizer joins words.
+type IdentifierCase int
+
+const (
+	// CamelCase joins words as "httpServerName".
+	CamelCase IdentifierCase = iota
+	// PascalCase joins words as "HttpServerName".
+	PascalCase
+	// SnakeCase joins words as "http_server_name".
+	SnakeCase
+	// KebabCase joins words as "http-server-name".
+	KebabCase
+	// ScreamingSnakeCase joins words as "HTTP_SERVER_NAME".
+	ScreamingSnakeCase
+	// DotCase joins words as "http.server.name".
+	DotCase
+	// TrainCase joins words as "Http-Server-Name".
+	TrainCase
+)
+
+// String returns the name of the case.
+func (c IdentifierCase) String() string {
+	switch c {
+	case CamelCase:
+		return "camelCase"
+	case PascalCase:
+		return "PascalCase"
+	case SnakeCase:
+		return "snake_case"
+	case KebabCase:
+		return "kebab-case"
+	case ScreamingSnakeCase:
+		return "SCREAMING_SNAKE_CASE"
+	case DotCase:
+		return "dot.case"
+	case TrainCase:
+		return "Train-Case"
+	}
+
+	return "IdentifierCase(" + strconv.Itoa(int(c)) + ")"
+}
+
+// GoInitialisms is the list of initialisms the Go lint rules expect to keep
+// their case, for use with IdentifierCaseNormalizer.
+var GoInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP",
+	"HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA",
+	"SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID",
+	"URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// SplitWords splits an identifier or a phrase into words. Any character other
+// than a letter, a digit or a combining mark separates words, and words are
+// also split at camelCase humps, after acronyms and between letters and
+// digits, so "HTTPServer_v2" gives "HTTP", "Server", "v" and "2". A plural
+// "s" stays with its acronym: "userIDs" gives "user" and "IDs".
+func SplitWords(input string) []string {
+	var words []string
+
+	runes := []rune(input)
+	start := -1
+	for i, r := range runes {
+		if !isIdentifierRune(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		if unicode.IsMark(r) {
+			continue
+		}
+		// Combining marks belong to the letter they follow.
+		prev := runes[i-1]
+		for j := i - 1; j > start && unicode.IsMark(prev); j-- {
+			prev = runes[j-1]
+		}
+		switch {
+		case unicode.IsDigit(prev) != unicode.IsDigit(r),
+			unicode.IsLower(prev) && unicode.IsUpper(r),
+			unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) &&
+				!isPluralAcronym(runes, i+1):
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
+// isPluralAcronym reports whether runes[i] is a lowercase "s" ending the
+// word after an acronym, as in "IDs".
+func isPluralAcronym(runes []rune, i int) bool {
+	return runes[i] == 's' && (i+1 == len(runes) || !unicode.IsLower(runes[i+1]))
+}
+
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// IdentifierCaseNormalizer splits the input string with SplitWords and joins
+// the words in the given case. In camelCase, PascalCase and Train-Case, words
+// listed in initialisms are written as given, except at the start of a
+// camelCase identifier, so with GoInitialisms "user id" becomes "userID" and
+// "id token" becomes "idToken".
+func IdentifierCaseNormalizer(c IdentifierCase, initialisms ...string) Normalizer {
+	known := map[string]string{}
+	for _, i := range initialisms {
+		known[CaseFoldNormalizer(i)] = i
+	}
+
+	return func(input string) string {
+		return formatIdentifier(SplitWords(input), c, known)
+	}
+}
+
+// CamelCaseNormalizer converts the input string to camelCase.
+func CamelCaseNormalizer(input string) string {
+	return formatIdentifier(SplitWords(input), CamelCase, nil)
+}
+
+// PascalCaseNormalizer converts the input string to PascalCase.
+func PascalCaseNormalizer(input string) string {
+	return formatIdentifier(SplitWords(input), PascalCase, nil)
+}
+
+// SnakeCaseNormalizer converts the input string to snake_case.
+func SnakeCaseNormalizer(input string) string {
+	return formatIdentifier(SplitWords(input), SnakeCase, nil)
+}
+
+// KebabCaseNormalizer converts the input string to kebab-case.
+func KebabCaseNormalizer(input string) string {
+	return formatIdentifier(SplitWords(input), KebabCase, nil)
+}
+
+// ScreamingSnakeCaseNormalizer converts the input string to SCREAMING_SNAKE_CASE.
+func ScreamingSnakeCaseNormalizer(input string) string {
+	return formatIdentifier(SplitWords(input), ScreamingSnakeCase, nil)
+}
+
+// DotCaseNormalizer converts the input string to dot.case.
+func DotCaseNormalizer(input string) string {
+	return formatIdentifier(SplitWords(input), DotCase, nil)
+}
+
+// TrainCaseNormalizer converts the input string to Train-Case.
+func TrainCaseNormalizer(input string) string {
+	return formatIdentifier(SplitWords(input), TrainCase, nil)
+}
+
+func formatIdentifier(words []string, c IdentifierCase, initialisms map[string]string) string {
+	separator := ""
+	switch c {
+	case SnakeCase, ScreamingSnakeCase:
+		separator = "_"
+	case KebabCase, TrainCase:
+		separator = "-"
+	case DotCase:
+		separator = "."
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		initialism, ok := initialisms[CaseFoldNormalizer(word)]
+		// SplitWords separates digits, which would break "UTF8" apart.
+		if i+1 < len(words) {
+			if s, found := initialisms[CaseFoldNormalizer(word+words[i+1])]; found {
+				word, initialism, ok = word+words[i+1], s, true
+				i++
+			}
+		}
+
+		if b.Len() > 0 {
+			b.WriteString(separator)
+		}
+		switch {
+		case c == ScreamingSnakeCase:
+			b.WriteString(toUpper(word, LocaleDefault))
+		case c == SnakeCase || c == KebabCase || c == DotCase || c == CamelCase && b.Len() == 0:
+			b.WriteString(toLower(word, LocaleDefault))
+		case ok:
+			b.WriteString(initialism)
+		default:
+			b.WriteString(capitalize(word))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package textn8r
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"helloWorld", []string{"hello", "World"}},
+		{"HelloWorld", []string{"Hello", "World"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"getHTTPResponseCode", []string{"get", "HTTP", "Response", "Code"}},
+		{"userID", []string{"user", "ID"}},
+		{"IDs", []string{"IDs"}},
+		{"userIDs", []string{"user", "IDs"}},
+		{"listAPIsByName", []string{"list", "APIs", "By", "Name"}},
+		{"HTTPSocket", []string{"HTTP", "Socket"}},
+		{"version2Beta", []string{"version", "2", "Beta"}},
+		{"base64Encode", []string{"base", "64", "Encode"}},
+		{"snake_case_name", []string{"snake", "case", "name"}},
+		{"kebab-case--name", []string{"kebab", "case", "name"}},
+		{"  Hello, World!  ", []string{"Hello", "World"}},
+		{"SCREAMING_SNAKE", []string{"SCREAMING", "SNAKE"}},
+		{"straßeName", []string{"straße", "Name"}},
+		{"Caf\u00E9Noir", []string{"Caf\u00E9", "Noir"}},
+		{"Cafe\u0301Noir", []string{"Cafe\u0301", "Noir"}},
+		{"", nil},
+		{"___", nil},
+	}
+
+	for _, tt := range tests {
+		result := SplitWords(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("SplitWords(%q) = %q; want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestIdentifierCaseNormalizers(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalizer Normalizer
+		input      string
+		expected   string
+	}{
+		{"CamelCaseNormalizer", CamelCaseNormalizer, "HTTP server name", "httpServerName"},
+		{"CamelCaseNormalizer", CamelCaseNormalizer, "user_id", "userId"},
+		{"PascalCaseNormalizer", PascalCaseNormalizer, "http_server_name", "HttpServerName"},
+		{"PascalCaseNormalizer", PascalCaseNormalizer, "getHTTPResponse", "GetHttpResponse"},
+		{"SnakeCaseNormalizer", SnakeCaseNormalizer, "HTTPServerName", "http_server_name"},
+		{"SnakeCaseNormalizer", SnakeCaseNormalizer, "userIDs", "user_ids"},
+		{"SnakeCaseNormalizer", SnakeCaseNormalizer, "version2Beta", "version_2_beta"},
+		{"KebabCaseNormalizer", KebabCaseNormalizer, "userAccountID", "user-account-id"},
+		{"ScreamingSnakeCaseNormalizer", ScreamingSnakeCaseNormalizer, "maxRetryCount", "MAX_RETRY_COUNT"},
+		{"ScreamingSnakeCaseNormalizer", ScreamingSnakeCaseNormalizer, "straßeName", "STRASSE_NAME"},
+		{"DotCaseNormalizer", DotCaseNormalizer, "Config File-Path", "config.file.path"},
+		{"TrainCaseNormalizer", TrainCaseNormalizer, "content_type", "Content-Type"},
+		{"CamelCaseNormalizer", CamelCaseNormalizer, "", ""},
+	}
+
+	for _, tt := range tests {
+		result := tt.normalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("%s(%q) = %q; want %q", tt.name, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestIdentifierCaseNormalizer(t *testing.T) {
+	tests := []struct {
+		style       IdentifierCase
+		initialisms []string
+		input       string
+		expected    string
+	}{
+		{CamelCase, GoInitialisms, "user_id", "userID"},
+		{CamelCase, GoInitialisms, "id_token", "idToken"},
+		{CamelCase, GoInitialisms, "http-server-url", "httpServerURL"},
+		{PascalCase, GoInitialisms, "http_server_url", "HTTPServerURL"},
+		{PascalCase, GoInitialisms, "utf8_decoder", "UTF8Decoder"},
+		{PascalCase, []string{"OAuth"}, "oauth_token", "OAuthToken"},
+		{TrainCase, []string{"WWW"}, "www authenticate", "WWW-Authenticate"},
+		{SnakeCase, GoInitialisms, "UserID", "user_id"},
+		{SnakeCase, GoInitialisms, "UTF8Decoder", "utf8_decoder"},
+		{ScreamingSnakeCase, GoInitialisms, "apiKey", "API_KEY"},
+	}
+
+	for _, tt := range tests {
+		result := IdentifierCaseNormalizer(tt.style, tt.initialisms...).Apply(tt.input)
+		if result != tt.expected {
+			t.Errorf("IdentifierCaseNormalizer(%s).Apply(%q) = %q; want %q", tt.style, tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package textn8r
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parameters of Punycode, from RFC 3492.
+const (
+	punycodeBase        = 36
+	punycodeTMin        = 1
+	punycodeTMax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+)
+
+// Limits of domain names, in bytes of their ASCII form.
+const (
+	maxLabelLength  = 63
+	maxDomainLength = 253
+)
+
+// domainToASCII returns the ASCII form of a domain name, as in the ToASCII
+// operation of IDNA: labels are lowercased and mapped to NFKC, and labels
+// with other than ASCII characters are encoded with Punycode and prefixed
+// with "xn--". Ideographic full stops separate labels like ".", and a final
+// dot is kept. Labels must be made of letters, digits, hyphens and
+// underscores, and must not start or end with a hyphen.
+func domainToASCII(domain string) (string, error) {
+	domain = strings.NewReplacer("。", ".", "．", ".", "｡", ".").Replace(domain)
+	root := strings.HasSuffix(domain, ".")
+	if root {
+		domain = domain[:len(domain)-1]
+	}
+	if domain == "" {
+		return "", errors.New("empty domain")
+	}
+
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			label = strings.ToLower(label)
+		} else if label = strings.ToLower(NFKCNormalizer(label)); !isASCII(label) {
+			label = "xn--" + punycode(label)
+		}
+		if err := checkLabel(label); err != nil {
+			return "", err
+		}
+		labels[i] = label
+	}
+
+	ascii := strings.Join(labels, ".")
+	if len(ascii) > maxDomainLength {
+		return "", errors.New("domain longer than 253 bytes")
+	}
+	if root {
+		ascii += "."
+	}
+
+	return ascii, nil
+}
+
+// checkLabel checks the ASCII form of a label.
+func checkLabel(label string) error {
+	switch {
+	case label == "":
+		return errors.New("empty label")
+	case len(label) > maxLabelLength:
+		return errors.New("label " + label + " longer than 63 bytes")
+	case label[0] == '-' || label[len(label)-1] == '-':
+		return errors.New("label " + label + " starts or ends with a hyphen")
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !isASCIIAlphanumeric(c) && c != '-' && c != '_' {
+			return errors.New("invalid character " + strconv.QuoteRune(rune(c)) + " in label " + label)
+		}
+	}
+
+	return nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// punycode encodes the label with the Punycode algorithm of RFC 3492.
+func punycode(label string) string {
+	runes := []rune(label)
+	var out []byte
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	basic := len(out)
+	handled := basic
+	if basic > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := rune(punycodeInitialN), 0, punycodeInitialBias
+	for handled < len(runes) {
+		// The smallest code point not handled yet.
+		m := rune(utf8.MaxRune)
+		for _, r := range runes {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+		delta += int(m-n) * (handled + 1)
+		n = m
+
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := k - bias
+				if t < punycodeTMin {
+					t = punycodeTMin
+				} else if t > punycodeTMax {
+					t = punycodeTMax
+				}
+				if q < t {
+					break
+				}
+				out = append(out, punycodeDigit(t+(q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+			out = append(out, punycodeDigit(q))
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+
+	return string(out)
+}
+
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+
+	return byte('0' + d - 26)
+}
+
+// punycodeAdapt is the bias adaptation function of RFC 3492.
+func punycodeAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+
+	k := 0
+	for delta > (punycodeBase-punycodeTMin)*punycodeTMax/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
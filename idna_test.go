@@ -0,0 +1,60 @@
+package textn8r
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDomainToASCII(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{"Example.COM", "example.com", ""},
+		{"münchen.de", "xn--mnchen-3ya.de", ""},
+		{"Bücher.example", "xn--bcher-kva.example", ""},
+		{"例え。テスト", "xn--r8jz45g.xn--zckzah", ""},
+		{"ＥＸＡＭＰＬＥ．com", "example.com", ""},
+		{"xn--MNCHEN-3ya.de", "xn--mnchen-3ya.de", ""},
+		{"example.com.", "example.com.", ""},
+		{"_dmarc.example.com", "_dmarc.example.com", ""},
+		{"", "", "empty domain"},
+		{"a..b", "", "empty label"},
+		{"-a.b", "", "label -a starts or ends with a hyphen"},
+		{"a b.c", "", "invalid character ' ' in label a b"},
+		{"a." + strings.Repeat("b", 64), "", "label " + strings.Repeat("b", 64) + " longer than 63 bytes"},
+	}
+
+	for _, tt := range tests {
+		result, err := domainToASCII(tt.input)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("domainToASCII(%q) error = %v; want %s", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || result != tt.expected {
+			t.Errorf("domainToASCII(%q) = %q, %v; want %q", tt.input, result, err, tt.expected)
+		}
+	}
+}
+
+func TestPunycode(t *testing.T) {
+	// Samples from RFC 3492, section 7.1.
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+		{"Pročprostěnemluvíčesky", "Proprostnemluvesky-uyb24dma41a"},
+		{"3年B組金八先生", "3B-ww4c5e180e575a65lsy2b"},
+		{"ü", "tda"},
+	}
+
+	for _, tt := range tests {
+		if result := punycode(tt.input); result != tt.expected {
+			t.Errorf("punycode(%q) = %q; want %q", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,497 @@
+package textn8r
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONRule applies a normalizer to the strings selected by a selector.
+//
+// Selectors starting with "$" are paths such as "$.users[*].email", made of
+// ".name" or ["name"] for object members, ".*" for every member, [2] for an
+// array element, [*] for every element and "..name" for members at any
+// depth. Other selectors are key-name globs, in the syntax of path.Match,
+// such as "email" or "*_name", and select the values of the matching members
+// at any depth.
+//
+// Selecting an object or an array selects every string inside it.
+type JSONRule struct {
+	Selector   string
+	Normalizer NormalizerE
+}
+
+// JSONNormalizer normalizes strings inside JSON documents. It reads a stream
+// of JSON values, such as a single document or JSON Lines, and writes each of
+// them compacted on its own line. Member order and the text of numbers are
+// kept.
+type JSONNormalizer struct {
+	Rules []JSONRule
+	// Keys normalizes the names of every object member when not nil.
+	// Selectors match the names before normalization.
+	Keys NormalizerE
+	// Mode selects what happens when a normalizer fails. In StopOnError mode
+	// Normalize returns a *JSONError. In CollectErrors mode the string is
+	// left unchanged and the error is added to JSONStats.Errors.
+	Mode ErrorMode
+}
+
+// JSONStats counts the strings changed by a JSONNormalizer.
+type JSONStats struct {
+	// Documents is the number of top-level values.
+	Documents int
+	// Rules holds the counts of each of JSONNormalizer.Rules.
+	Rules []JSONRuleStats
+	// KeysChanged is the number of member names changed by
+	// JSONNormalizer.Keys.
+	KeysChanged int
+	// Errors holds the strings rejected in CollectErrors mode.
+	Errors []*JSONError
+}
+
+// JSONRuleStats counts the strings changed by a rule of a JSONNormalizer.
+type JSONRuleStats struct {
+	Selector string
+	Changed  int
+	Failed   int
+}
+
+// JSONError reports a problem with a JSON document.
+type JSONError struct {
+	// Path locates the string a normalizer rejected, as in
+	// "$.users[2].email". It is empty for syntax errors.
+	Path string
+	// Offset is the position in the input, in bytes, of syntax errors.
+	Offset int64
+	Err    error
+}
+
+func (e *JSONError) Error() string {
+	if e.Path == "" {
+		return "textn8r: json: offset " + strconv.FormatInt(e.Offset, 10) + ": " + strings.TrimPrefix(e.Err.Error(), "json: ")
+	}
+
+	return "textn8r: json: " + e.Path + ": " + strings.TrimPrefix(e.Err.Error(), "textn8r: ")
+}
+
+// Unwrap returns the error behind the problem.
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
+// jsonSegmentKind is the kind of a segment of a path selector.
+type jsonSegmentKind int
+
+const (
+	jsonMember jsonSegmentKind = iota
+	jsonAnyMember
+	jsonIndex
+	jsonAnyIndex
+	// jsonDescendant matches any number of members and elements.
+	jsonDescendant
+)
+
+type jsonSegment struct {
+	kind  jsonSegmentKind
+	name  string
+	index int
+}
+
+// jsonSelector is a compiled selector: a path, or a key-name glob when
+// segments is nil.
+type jsonSelector struct {
+	segments []jsonSegment
+	glob     string
+}
+
+// jsonPathElement is a member name or, when key is nil, an array index.
+type jsonPathElement struct {
+	key   *string
+	index int
+}
+
+func compileJSONSelector(selector string) (jsonSelector, error) {
+	if !strings.HasPrefix(selector, "$") {
+		if _, err := path.Match(selector, ""); err != nil || selector == "" {
+			return jsonSelector{}, errors.New("invalid key glob " + strconv.Quote(selector))
+		}
+		return jsonSelector{glob: selector}, nil
+	}
+
+	invalid := func(at int) error {
+		return errors.New("invalid selector " + strconv.Quote(selector) + " at offset " + strconv.Itoa(at))
+	}
+
+	segments := []jsonSegment{}
+	s := selector[1:]
+	for s != "" {
+		at := len(selector) - len(s)
+		switch {
+		case strings.HasPrefix(s, ".."):
+			segments = append(segments, jsonSegment{kind: jsonDescendant})
+			rest := s[2:]
+			if rest == "" || rest[0] == '.' {
+				return jsonSelector{}, invalid(at)
+			}
+			if rest[0] == '[' {
+				s = rest
+			} else {
+				s = s[1:]
+			}
+
+		case strings.HasPrefix(s, ".*"):
+			segments = append(segments, jsonSegment{kind: jsonAnyMember})
+			s = s[2:]
+
+		case strings.HasPrefix(s, "."):
+			end := strings.IndexAny(s[1:], ".[")
+			if end < 0 {
+				end = len(s) - 1
+			}
+			if end == 0 {
+				return jsonSelector{}, invalid(at)
+			}
+			segments = append(segments, jsonSegment{kind: jsonMember, name: s[1 : end+1]})
+			s = s[end+1:]
+
+		case strings.HasPrefix(s, "[*]"):
+			segments = append(segments, jsonSegment{kind: jsonAnyIndex})
+			s = s[3:]
+
+		case strings.HasPrefix(s, "[\"") || strings.HasPrefix(s, "['"):
+			quote := s[1]
+			end := strings.IndexByte(s[2:], quote)
+			if end < 0 || !strings.HasPrefix(s[2+end+1:], "]") {
+				return jsonSelector{}, invalid(at)
+			}
+			segments = append(segments, jsonSegment{kind: jsonMember, name: s[2 : 2+end]})
+			s = s[2+end+2:]
+
+		case strings.HasPrefix(s, "["):
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return jsonSelector{}, invalid(at)
+			}
+			index, err := strconv.Atoi(s[1:end])
+			if err != nil || index < 0 {
+				return jsonSelector{}, invalid(at)
+			}
+			segments = append(segments, jsonSegment{kind: jsonIndex, index: index})
+			s = s[end+1:]
+
+		default:
+			return jsonSelector{}, invalid(at)
+		}
+	}
+
+	return jsonSelector{segments: segments}, nil
+}
+
+// match reports whether the selector selects the value at the path or one of
+// the objects and arrays holding it.
+func (s jsonSelector) match(p []jsonPathElement) bool {
+	if s.segments == nil {
+		for _, e := range p {
+			if e.key != nil {
+				if ok, _ := path.Match(s.glob, *e.key); ok {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	return matchJSONPath(s.segments, p)
+}
+
+func matchJSONPath(segments []jsonSegment, p []jsonPathElement) bool {
+	if len(segments) == 0 {
+		return true
+	}
+	if len(p) == 0 {
+		return false
+	}
+
+	e := p[0]
+	switch seg := segments[0]; seg.kind {
+	case jsonMember:
+		return e.key != nil && *e.key == seg.name && matchJSONPath(segments[1:], p[1:])
+	case jsonAnyMember:
+		return e.key != nil && matchJSONPath(segments[1:], p[1:])
+	case jsonIndex:
+		return e.key == nil && e.index == seg.index && matchJSONPath(segments[1:], p[1:])
+	case jsonAnyIndex:
+		return e.key == nil && matchJSONPath(segments[1:], p[1:])
+	}
+
+	// A descendant segment skips any number of elements.
+	for i := range p {
+		if matchJSONPath(segments[1:], p[i:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatJSONPath returns the path as a selector.
+func formatJSONPath(p []jsonPathElement) string {
+	var sb strings.Builder
+	sb.WriteByte('$')
+	for _, e := range p {
+		switch {
+		case e.key == nil:
+			sb.WriteString("[" + strconv.Itoa(e.index) + "]")
+		case isJSONPathName(*e.key):
+			sb.WriteString("." + *e.key)
+		default:
+			sb.WriteString("[" + strconv.Quote(*e.key) + "]")
+		}
+	}
+
+	return sb.String()
+}
+
+func isJSONPathName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '_' && !isASCIIAlphanumeric(c) || i == 0 && isASCIIDigit(rune(c)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonFrame is an object or array being copied.
+type jsonFrame struct {
+	object bool
+	// n is the number of members or elements copied so far.
+	n int
+	// key is the name of the current member.
+	key       string
+	expectKey bool
+}
+
+// Normalize copies the JSON values read from r to w, normalizing the selected
+// strings, and returns the counts of changed strings. Each value is written
+// on its own line.
+func (j *JSONNormalizer) Normalize(w io.Writer, r io.Reader) (*JSONStats, error) {
+	selectors := make([]jsonSelector, len(j.Rules))
+	stats := &JSONStats{Rules: make([]JSONRuleStats, len(j.Rules))}
+	for i, rule := range j.Rules {
+		selector, err := compileJSONSelector(rule.Selector)
+		if err != nil {
+			return nil, &ArgumentError{Normalizer: "json", Param: "Selector", Reason: err.Error()}
+		}
+		selectors[i] = selector
+		stats.Rules[i].Selector = rule.Selector
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	bw := bufio.NewWriter(w)
+	err := j.copy(bw, dec, selectors, stats)
+	if flushErr := bw.Flush(); err == nil {
+		err = flushErr
+	}
+
+	return stats, err
+}
+
+func (j *JSONNormalizer) copy(w *bufio.Writer, dec *json.Decoder, selectors []jsonSelector, stats *JSONStats) error {
+	var stack []jsonFrame
+	var p []jsonPathElement
+	var buf []byte
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			if len(stack) > 0 {
+				return &JSONError{Offset: dec.InputOffset(), Err: io.ErrUnexpectedEOF}
+			}
+			return nil
+		}
+		if err != nil {
+			return &JSONError{Offset: dec.InputOffset(), Err: err}
+		}
+
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = &stack[len(stack)-1]
+		}
+
+		// Member names.
+		if top != nil && top.object && top.expectKey {
+			if d, ok := tok.(json.Delim); ok && d == '}' {
+				stack = stack[:len(stack)-1]
+				w.WriteByte('}')
+				stack = j.completeValue(w, stack, stats)
+				continue
+			}
+			key := tok.(string)
+			top.key, top.expectKey = key, false
+			if top.n > 0 {
+				w.WriteByte(',')
+			}
+			if j.Keys != nil {
+				normalized, err := j.Keys(key)
+				if err != nil {
+					jsonErr := &JSONError{Path: formatJSONPath(currentJSONPath(stack, p[:0])), Err: err}
+					if j.Mode != CollectErrors {
+						return jsonErr
+					}
+					stats.Errors = append(stats.Errors, jsonErr)
+				} else if normalized != key {
+					stats.KeysChanged++
+					key = normalized
+				}
+			}
+			buf = appendJSONString(buf[:0], key)
+			buf = append(buf, ':')
+			w.Write(buf)
+			continue
+		}
+
+		if d, ok := tok.(json.Delim); ok && (d == ']') {
+			stack = stack[:len(stack)-1]
+			w.WriteByte(']')
+			stack = j.completeValue(w, stack, stats)
+			continue
+		}
+
+		// Values.
+		if top != nil && !top.object && top.n > 0 {
+			w.WriteByte(',')
+		}
+		switch v := tok.(type) {
+		case json.Delim:
+			w.WriteByte(byte(v))
+			stack = append(stack, jsonFrame{object: v == '{', expectKey: v == '{'})
+			continue
+
+		case string:
+			if len(selectors) > 0 {
+				p = currentJSONPath(stack, p[:0])
+				for i, selector := range selectors {
+					if !selector.match(p) {
+						continue
+					}
+					normalized, err := j.Rules[i].Normalizer(v)
+					if err != nil {
+						stats.Rules[i].Failed++
+						jsonErr := &JSONError{Path: formatJSONPath(p), Err: err}
+						if j.Mode != CollectErrors {
+							return jsonErr
+						}
+						stats.Errors = append(stats.Errors, jsonErr)
+						continue
+					}
+					if normalized != v {
+						stats.Rules[i].Changed++
+						v = normalized
+					}
+				}
+			}
+			buf = appendJSONString(buf[:0], v)
+			w.Write(buf)
+
+		case json.Number:
+			w.WriteString(string(v))
+		case bool:
+			w.WriteString(strconv.FormatBool(v))
+		case nil:
+			w.WriteString("null")
+		}
+		stack = j.completeValue(w, stack, stats)
+	}
+}
+
+// completeValue records the end of a value in the enclosing object or array,
+// or ends the line after a top-level value.
+func (j *JSONNormalizer) completeValue(w *bufio.Writer, stack []jsonFrame, stats *JSONStats) []jsonFrame {
+	if len(stack) == 0 {
+		w.WriteByte('\n')
+		stats.Documents++
+		return stack
+	}
+
+	top := &stack[len(stack)-1]
+	top.n++
+	top.expectKey = top.object
+
+	return stack
+}
+
+// currentJSONPath appends the path of the current value to p.
+func currentJSONPath(stack []jsonFrame, p []jsonPathElement) []jsonPathElement {
+	for i := range stack {
+		if stack[i].object {
+			p = append(p, jsonPathElement{key: &stack[i].key})
+		} else {
+			p = append(p, jsonPathElement{index: stack[i].n})
+		}
+	}
+
+	return p
+}
+
+// appendJSONString appends s as a JSON string, without escaping HTML
+// characters.
+func appendJSONString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 || r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			switch r {
+			case utf8.RuneError:
+				dst = append(dst, `�`...)
+			case '\u2028':
+				dst = append(dst, `\u2028`...)
+			default:
+				dst = append(dst, `\u2029`...)
+			}
+			start = i + size
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+
+	return append(dst, '"')
+}
@@ -0,0 +1,163 @@
+package textn8r
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// normalizeJSON runs the normalizer on the input.
+func normalizeJSON(t *testing.T, j *JSONNormalizer, input string) (string, *JSONStats, error) {
+	t.Helper()
+	var sb strings.Builder
+	stats, err := j.Normalize(&sb, strings.NewReader(input))
+	return sb.String(), stats, err
+}
+
+func TestJSONNormalizer(t *testing.T) {
+	lower := Normalizers{TrimSpaceNormalizer, LowerCaseNormalizer}.NormalizerE()
+	upper := Normalizer(UpperCaseNormalizer).NormalizerE()
+
+	tests := []struct {
+		name     string
+		j        JSONNormalizer
+		input    string
+		expected string
+	}{
+		{
+			"path",
+			JSONNormalizer{Rules: []JSONRule{{"$.users[*].email", lower}}},
+			`{"users": [{"name": "Jane", "email": " JANE@X.COM "}, {"name": "Bob", "email": "Bob@X.com"}], "email": "KEEP"}`,
+			`{"users":[{"name":"Jane","email":"jane@x.com"},{"name":"Bob","email":"bob@x.com"}],"email":"KEEP"}` + "\n",
+		},
+		{
+			"numbers and order kept",
+			JSONNormalizer{Rules: []JSONRule{{"$.b", upper}}},
+			`{"z": 12345678901234567890.123456789e-5, "b": "x", "a": [1.0, -0, true, false, null]}`,
+			`{"z":12345678901234567890.123456789e-5,"b":"X","a":[1.0,-0,true,false,null]}` + "\n",
+		},
+		{
+			"key glob",
+			JSONNormalizer{Rules: []JSONRule{{"*_name", upper}}},
+			`{"first_name": "a", "nested": {"last_name": "b", "tags": ["c"]}, "nick": "d", "x_name": ["e", {"f": "g"}]}`,
+			`{"first_name":"A","nested":{"last_name":"B","tags":["c"]},"nick":"d","x_name":["E",{"f":"G"}]}` + "\n",
+		},
+		{
+			"descendants",
+			JSONNormalizer{Rules: []JSONRule{{"$..id", upper}}},
+			`{"id": "a", "b": [{"id": "c"}, {"c": {"id": "d"}}]}`,
+			`{"id":"A","b":[{"id":"C"},{"c":{"id":"D"}}]}` + "\n",
+		},
+		{
+			"index and quoted member",
+			JSONNormalizer{Rules: []JSONRule{{`$["a b"][1]`, upper}, {"$.c.*", upper}}},
+			`{"a b": ["x", "y", "z"], "c": {"d": "e", "f": ["g"]}}`,
+			`{"a b":["x","Y","z"],"c":{"d":"E","f":["G"]}}` + "\n",
+		},
+		{
+			"object selected",
+			JSONNormalizer{Rules: []JSONRule{{"$.address", upper}}},
+			`{"address": {"street": "main st", "lines": ["a", "b"], "zip": 12}, "name": "n"}`,
+			`{"address":{"street":"MAIN ST","lines":["A","B"],"zip":12},"name":"n"}` + "\n",
+		},
+		{
+			"keys",
+			JSONNormalizer{Keys: Normalizer(SnakeCaseNormalizer).NormalizerE(), Rules: []JSONRule{{"$.userName", upper}}},
+			`{"userName": "jane", "homeAddress": {"zipCode": "x"}}`,
+			`{"user_name":"JANE","home_address":{"zip_code":"x"}}` + "\n",
+		},
+		{
+			"json lines",
+			JSONNormalizer{Rules: []JSONRule{{"$.tag", upper}}},
+			"{\"tag\": \"a\"}\n{\"tag\": \"b\"}\n\n\"c\"\n[]\n{}\n",
+			"{\"tag\":\"A\"}\n{\"tag\":\"B\"}\n\"c\"\n[]\n{}\n",
+		},
+		{
+			"root",
+			JSONNormalizer{Rules: []JSONRule{{"$", upper}}},
+			`"a" ["b", {"c": "d"}]`,
+			"\"A\"\n[\"B\",{\"c\":\"D\"}]\n",
+		},
+		{
+			"escapes",
+			JSONNormalizer{Rules: []JSONRule{{"$.s", Normalizer(ReplaceTabNormalizer("\"\\\n")).NormalizerE()}}},
+			`{"s": "<a>\t&\u2028\u0001é"}`,
+			`{"s":"<a>\"\\\n&\u2028\u0001é"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		result, _, err := normalizeJSON(t, &tt.j, tt.input)
+		if err != nil {
+			t.Errorf("%s: Normalize() error = %v", tt.name, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("%s: Normalize(%s) = %s; want %s", tt.name, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestJSONNormalizerStats(t *testing.T) {
+	j := &JSONNormalizer{
+		Rules: []JSONRule{
+			{"$[*].email", Normalizer(LowerCaseNormalizer).NormalizerE()},
+			{"email", requireAt},
+		},
+		Keys: Normalizer(LowerCaseNormalizer).NormalizerE(),
+		Mode: CollectErrors,
+	}
+
+	input := `[{"Email": "A@X"}, {"email": "b@x"}, {"email": "C"}]`
+	result, stats, err := normalizeJSON(t, j, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `[{"email":"A@X"},{"email":"b@x"},{"email":"c"}]` + "\n"; result != expected {
+		t.Errorf("Normalize() = %s; want %s", result, expected)
+	}
+
+	if stats.Documents != 1 || stats.KeysChanged != 1 {
+		t.Errorf("Documents, KeysChanged = %d, %d; want 1, 1", stats.Documents, stats.KeysChanged)
+	}
+	expected := []JSONRuleStats{{"$[*].email", 1, 0}, {"email", 0, 1}}
+	for i, rule := range stats.Rules {
+		if rule != expected[i] {
+			t.Errorf("Rules[%d] = %+v; want %+v", i, rule, expected[i])
+		}
+	}
+	if len(stats.Errors) != 1 || stats.Errors[0].Path != "$[2].email" {
+		t.Errorf("Errors = %v; want one error at $[2].email", stats.Errors)
+	}
+}
+
+func TestJSONNormalizerErrors(t *testing.T) {
+	upper := Normalizer(UpperCaseNormalizer).NormalizerE()
+	tests := []struct {
+		name     string
+		j        JSONNormalizer
+		input    string
+		expected string
+	}{
+		{"rejected", JSONNormalizer{Rules: []JSONRule{{"$.a[*]", requireAt}}}, `{"a": ["@", "b"]}`, `textn8r: json: $.a[1]: invalid input "b": missing "@"`},
+		{"quoted path", JSONNormalizer{Rules: []JSONRule{{"$.*", requireAt}}}, `{"a-b": "c"}`, `textn8r: json: $["a-b"]: invalid input "c": missing "@"`},
+		{"syntax", JSONNormalizer{Rules: []JSONRule{{"$.a", upper}}}, `{"a": "b",}`, `textn8r: json: offset 9: invalid character ',' looking for beginning of value`},
+		{"truncated", JSONNormalizer{Rules: []JSONRule{{"$.a", upper}}}, `{"a": ["b"`, `textn8r: json: offset 10: unexpected EOF`},
+		{"selector", JSONNormalizer{Rules: []JSONRule{{"$.a[x]", upper}}}, `{}`, `textn8r: normalizer "json": parameter "Selector": invalid selector "$.a[x]" at offset 3`},
+		{"descendant", JSONNormalizer{Rules: []JSONRule{{"$..", upper}}}, `{}`, `textn8r: normalizer "json": parameter "Selector": invalid selector "$.." at offset 1`},
+		{"glob", JSONNormalizer{Rules: []JSONRule{{"[a", upper}}}, `{}`, `textn8r: normalizer "json": parameter "Selector": invalid key glob "[a"`},
+	}
+
+	for _, tt := range tests {
+		_, _, err := normalizeJSON(t, &tt.j, tt.input)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("%s: Normalize(%s) error = %v; want %s", tt.name, tt.input, err, tt.expected)
+		}
+	}
+
+	_, _, err := normalizeJSON(t, &JSONNormalizer{Rules: []JSONRule{{"$", requireAt}}}, `"x"`)
+	var invalid *InvalidInputError
+	if !errors.As(err, &invalid) {
+		t.Errorf("Normalize() error = %v; want an *InvalidInputError", err)
+	}
+}
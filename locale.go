@@ -0,0 +1,30 @@
+package textn8r
+
+import (
+	"strings"
+)
+
+// Locale identifies a language by its BCP 47 tag, such as "de" or "pt-BR".
+// Only the language subtag is used to select language specific rules.
+type Locale string
+
+// Locales with language specific rules in this package.
+const (
+	LocaleDefault     Locale = ""
+	LocaleGerman      Locale = "de"
+	LocaleDanish      Locale = "da"
+	LocaleNorwegian   Locale = "nb"
+	LocaleSwedish     Locale = "sv"
+	LocaleSpanish     Locale = "es"
+	LocaleTurkish     Locale = "tr"
+	LocaleAzerbaijani Locale = "az"
+)
+
+// Language returns the lowercase language subtag of the locale, so both
+// "de-AT" and "DE_at" return "de".
+func (l Locale) Language() Locale {
+	language, _, _ := strings.Cut(string(l), "-")
+	language, _, _ = strings.Cut(language, "_")
+
+	return Locale(strings.ToLower(language))
+}
@@ -0,0 +1,302 @@
+//go:build ignore
+
+// This program generates tables.go from the Unicode Character Database.
+// Run it with:
+//
+//	go run maketables.go
+//
+// By default the UCD files are downloaded from unicode.org. Use -ucd to read
+// them from a local directory instead.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const unicodeVersion = "15.0.0"
+
+var (
+	ucdDir   = flag.String("ucd", "", "local directory containing the UCD files; empty means download them")
+	output   = flag.String("output", "tables.go", "output file")
+	testdata = flag.Bool("testdata", false, "also download NormalizationTest.txt into testdata/")
+)
+
+const ucdURL = "https://www.unicode.org/Public/" + unicodeVersion + "/ucd/"
+
+func main() {
+	flag.Parse()
+
+	ccc := map[rune]uint8{}
+	canonical := map[rune][]rune{}
+	compat := map[rune][]rune{}
+
+	parse("UnicodeData.txt", func(fields []string) {
+		r := parseRune(fields[0])
+		if c, _ := strconv.Atoi(fields[3]); c != 0 {
+			ccc[r] = uint8(c)
+		}
+		if fields[5] == "" {
+			return
+		}
+		d := strings.Fields(fields[5])
+		if strings.HasPrefix(d[0], "<") {
+			compat[r] = parseRunes(d[1:])
+			return
+		}
+		canonical[r] = parseRunes(d)
+	})
+
+	excluded := map[rune]bool{}
+	parse("DerivedNormalizationProps.txt", func(fields []string) {
+		if fields[1] != "Full_Composition_Exclusion" {
+			return
+		}
+		lo, hi := parseRange(fields[0])
+		for r := lo; r <= hi; r++ {
+			excluded[r] = true
+		}
+	})
+
+	// Expand every decomposition recursively so the runtime never has to.
+	var expand func(r rune, compatible bool) []rune
+	expand = func(r rune, compatible bool) []rune {
+		d, ok := canonical[r]
+		if !ok && compatible {
+			d, ok = compat[r]
+		}
+		if !ok {
+			return []rune{r}
+		}
+		var out []rune
+		for _, c := range d {
+			out = append(out, expand(c, compatible)...)
+		}
+		return out
+	}
+
+	fullCanonical := map[rune]string{}
+	for r := range canonical {
+		fullCanonical[r] = string(expand(r, false))
+	}
+	fullCompat := map[rune]string{}
+	for r := range compat {
+		fullCompat[r] = string(expand(r, true))
+	}
+	for r := range canonical {
+		if s := string(expand(r, true)); s != fullCanonical[r] {
+			fullCompat[r] = s
+		}
+	}
+
+	upper := map[rune]string{}
+	lower := map[rune]string{}
+	title := map[rune]string{}
+	parse("SpecialCasing.txt", func(fields []string) {
+		if len(fields) > 4 && fields[4] != "" {
+			// Conditional mappings are implemented in case.go.
+			return
+		}
+		r := parseRune(fields[0])
+		for i, m := range []map[rune]string{lower, title, upper} {
+			if s := string(parseRunes(strings.Fields(fields[i+1]))); s != string(r) {
+				m[r] = s
+			}
+		}
+	})
+
+	folding := map[rune]string{}
+	parse("CaseFolding.txt", func(fields []string) {
+		// C and F together make the full case folding.
+		if fields[1] != "C" && fields[1] != "F" {
+			return
+		}
+		folding[parseRune(fields[0])] = string(parseRunes(strings.Fields(fields[2])))
+	})
+
+	compositions := map[uint64]rune{}
+	for r, d := range canonical {
+		if excluded[r] || len(d) != 2 {
+			continue
+		}
+		compositions[uint64(d[0])<<32|uint64(d[1])] = r
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by maketables.go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package textn8r\n\n")
+	fmt.Fprintf(&buf, "// UnicodeVersion is the Unicode version the embedded tables are derived from.\n")
+	fmt.Fprintf(&buf, "const UnicodeVersion = %q\n\n", unicodeVersion)
+
+	fmt.Fprintf(&buf, "// combiningClassTable holds the non-zero canonical combining classes.\n")
+	fmt.Fprintf(&buf, "var combiningClassTable = map[rune]uint8{\n")
+	for _, r := range sortedKeys(ccc) {
+		fmt.Fprintf(&buf, "\t0x%04X: %d,\n", r, ccc[r])
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	writeStrings(&buf, "canonicalDecompositionTable", "holds the full canonical decomposition of each rune that has one.", fullCanonical)
+	writeStrings(&buf, "compatibilityDecompositionTable", "holds the full compatibility decomposition of each rune whose\n// compatibility decomposition differs from its canonical one.", fullCompat)
+
+	writeStrings(&buf, "specialUpperTable", "holds the unconditional full uppercase mappings of\n// SpecialCasing.txt.", upper)
+	writeStrings(&buf, "specialLowerTable", "holds the unconditional full lowercase mappings of\n// SpecialCasing.txt.", lower)
+	writeStrings(&buf, "specialTitleTable", "holds the unconditional full titlecase mappings of\n// SpecialCasing.txt.", title)
+	writeStrings(&buf, "caseFoldTable", "holds the full case folding of CaseFolding.txt, the\n// entries with status C and F.", folding)
+
+	fmt.Fprintf(&buf, "// compositionTable maps a pair of runes, packed as first<<32|second, to\n")
+	fmt.Fprintf(&buf, "// their primary composite. Composition exclusions are already removed.\n")
+	fmt.Fprintf(&buf, "var compositionTable = map[uint64]rune{\n")
+	keys := make([]uint64, 0, len(compositions))
+	for k := range compositions {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "\t0x%04X<<32 | 0x%04X: 0x%04X,\n", k>>32, k&0xFFFFFFFF, compositions[k])
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting output: %v", err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	if *testdata {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			log.Fatal(err)
+		}
+		rc := open("NormalizationTest.txt")
+		defer rc.Close()
+		f, err := os.Create(filepath.Join("testdata", "NormalizationTest.txt"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := io.Copy(f, rc); err != nil {
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func writeStrings(buf *bytes.Buffer, name, doc string, m map[rune]string) {
+	fmt.Fprintf(buf, "// %s %s\n", name, doc)
+	fmt.Fprintf(buf, "var %s = map[rune]string{\n", name)
+	for _, r := range sortedKeys(m) {
+		fmt.Fprintf(buf, "\t0x%04X: %s,\n", r, quoteRunes(m[r]))
+	}
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// quoteRunes quotes s using \u escapes so the table stays readable even for
+// combining marks.
+func quoteRunes(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		if r < 0x80 && strconv.IsPrint(r) && r != '"' && r != '\\' {
+			b.WriteRune(r)
+			continue
+		}
+		if r > 0xFFFF {
+			fmt.Fprintf(&b, `\U%08X`, r)
+			continue
+		}
+		fmt.Fprintf(&b, `\u%04X`, r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func sortedKeys[V any](m map[rune]V) []rune {
+	keys := make([]rune, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// open returns the named UCD file, either from -ucd or from unicode.org.
+func open(name string) io.ReadCloser {
+	if *ucdDir != "" {
+		f, err := os.Open(filepath.Join(*ucdDir, name))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return f
+	}
+	resp, err := http.Get(ucdURL + name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("fetching %s: %s", name, resp.Status)
+	}
+	return resp.Body
+}
+
+// parse calls fn with the semicolon separated fields of every data line in
+// the named UCD file. Comments and blank lines are skipped.
+func parse(name string, fn func(fields []string)) {
+	rc := open(name)
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		fn(fields)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("reading %s: %v", name, err)
+	}
+}
+
+func parseRune(s string) rune {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		log.Fatalf("invalid code point %q: %v", s, err)
+	}
+	return rune(v)
+}
+
+func parseRunes(fields []string) []rune {
+	runes := make([]rune, len(fields))
+	for i, f := range fields {
+		runes[i] = parseRune(f)
+	}
+	return runes
+}
+
+func parseRange(s string) (rune, rune) {
+	if lo, hi, ok := strings.Cut(s, ".."); ok {
+		return parseRune(lo), parseRune(hi)
+	}
+	r := parseRune(s)
+	return r, r
+}
@@ -0,0 +1,130 @@
+//go:build ignore
+
+// This program generates translit.bin, the compressed ASCII transliteration
+// table of the Basic Multilingual Plane. Run it with:
+//
+//	go run maketranslit.go
+//
+// The source is the Unidecode table maintained at github.com/rainycape/unidecode
+// (Apache License 2.0), itself derived from Sean M. Burke's Text::Unidecode.
+// Every line of the source has the form
+//
+//	0x4e00: "Yi "
+//
+// The output is zlib compressed and holds, for every code point from U+0000 to
+// U+FFFF, a length byte followed by the transliteration. The length 0xFF marks
+// a code point without transliteration.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const sourceURL = "https://raw.githubusercontent.com/rainycape/unidecode/master/table.txt"
+
+var (
+	source = flag.String("source", "", "local copy of the Unidecode table; empty means download it")
+	output = flag.String("output", "translit.bin", "output file")
+)
+
+// unmapped marks a code point without transliteration.
+const unmapped = 0xFF
+
+func main() {
+	flag.Parse()
+
+	var table [0x10000]*string
+	scanner := bufio.NewScanner(open())
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "0x") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			log.Fatalf("invalid line %q", line)
+		}
+		cp, err := strconv.ParseUint(key, 0, 32)
+		if err != nil || cp > 0xFFFF {
+			log.Fatalf("invalid code point in %q", line)
+		}
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			log.Fatalf("invalid transliteration in %q: %v", line, err)
+		}
+		// "[?]" is how Unidecode spells "unknown"; leave it to the fallback.
+		if s == "[?]" {
+			continue
+		}
+		if len(s) >= unmapped {
+			log.Fatalf("transliteration of U+%04X too long", cp)
+		}
+		for i := 0; i < len(s); i++ {
+			if s[i] >= 0x80 {
+				log.Fatalf("transliteration of U+%04X is not ASCII", cp)
+			}
+		}
+		table[cp] = &s
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	var raw bytes.Buffer
+	for _, s := range table {
+		if s == nil {
+			raw.WriteByte(unmapped)
+			continue
+		}
+		raw.WriteByte(byte(len(*s)))
+		raw.WriteString(*s)
+	}
+
+	var out bytes.Buffer
+	w, err := zlib.NewWriterLevel(&out, zlib.BestCompression)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := w.Write(raw.Bytes()); err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, out.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func open() io.Reader {
+	if *source != "" {
+		data, err := os.ReadFile(*source)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return bytes.NewReader(data)
+	}
+
+	resp, err := http.Get(sourceURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("fetching %s: %s", sourceURL, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return bytes.NewReader(data)
+}
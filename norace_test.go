@@ -0,0 +1,5 @@
+//go:build !race
+
+package textn8r
+
+const raceEnabled = false
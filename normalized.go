@@ -0,0 +1,143 @@
+package textn8r
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+// Policy supplies the pipeline of a Normalized type. Policies are used
+// through their zero value, so they are usually empty structs:
+//
+//	type CodePolicy struct{}
+//
+//	func (CodePolicy) Normalize(s string) (string, error) {
+//		return UpperCaseNormalizer(TrimSpaceNormalizer(s)), nil
+//	}
+type Policy interface {
+	Normalize(input string) (string, error)
+}
+
+// Normalized is a string normalized by the policy P whenever it is decoded
+// from JSON or text, scanned from a database or written to one. Fields of
+// this type hold normalized values without calling the normalizers by hand:
+//
+//	type User struct {
+//		Email textn8r.EmailString `json:"email" db:"email"`
+//	}
+//
+// Values converted directly from strings, such as EmailString("A@B"), are
+// not normalized until they are written; NewNormalized normalizes them.
+type Normalized[P Policy] string
+
+// NewNormalized returns the input normalized by the policy P.
+func NewNormalized[P Policy](input string) (Normalized[P], error) {
+	var n Normalized[P]
+	err := n.set(input)
+	return n, err
+}
+
+// set normalizes the input and stores it, leaving n unchanged when the
+// policy rejects it.
+func (n *Normalized[P]) set(input string) error {
+	var p P
+	output, err := p.Normalize(input)
+	if err != nil {
+		return err
+	}
+	*n = Normalized[P](output)
+
+	return nil
+}
+
+// String returns the value as a string.
+func (n Normalized[P]) String() string {
+	return string(n)
+}
+
+// Scan implements sql.Scanner for string and []byte columns. NULL is an
+// error; sql.Null[Normalized[P]] holds nullable columns.
+func (n *Normalized[P]) Scan(src any) error {
+	switch src := src.(type) {
+	case string:
+		return n.set(src)
+	case []byte:
+		return n.set(string(src))
+	case nil:
+		return &ArgumentError{Normalizer: "normalized", Reason: "cannot scan NULL"}
+	}
+
+	return &ArgumentError{Normalizer: "normalized", Reason: "cannot scan a column of type " + reflect.TypeOf(src).String()}
+}
+
+// Value implements driver.Valuer, normalizing the value before it is
+// written.
+func (n Normalized[P]) Value() (driver.Value, error) {
+	if err := n.set(string(n)); err != nil {
+		return nil, err
+	}
+
+	return string(n), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (n *Normalized[P]) UnmarshalText(text []byte) error {
+	return n.set(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler for JSON strings. null leaves the
+// value unchanged, as for other types.
+func (n *Normalized[P]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return n.set(s)
+}
+
+// defaultSlugger makes the slugs of SlugPolicy.
+var defaultSlugger Slugger
+
+// TrimmedLowerPolicy trims white space and lowercases.
+type TrimmedLowerPolicy struct{}
+
+// Normalize implements Policy.
+func (TrimmedLowerPolicy) Normalize(input string) (string, error) {
+	return LowerCaseNormalizer(TrimSpaceNormalizer(input)), nil
+}
+
+// SlugPolicy turns strings into URL slugs with the zero Slugger.
+type SlugPolicy struct{}
+
+// Normalize implements Policy.
+func (SlugPolicy) Normalize(input string) (string, error) {
+	return defaultSlugger.Slug(input), nil
+}
+
+// EmailPolicy makes email addresses canonical with EmailNormalizer, without
+// provider rules: the domain is lowercased and in the ASCII form of IDNA, and
+// the case-sensitive local part is kept. Strings that are not a single
+// address are rejected.
+type EmailPolicy struct{}
+
+// emailNormalizer makes the addresses of EmailPolicy.
+var emailNormalizer = EmailNormalizer(EmailOptions{})
+
+// Normalize implements Policy.
+func (EmailPolicy) Normalize(input string) (string, error) {
+	return emailNormalizer(input)
+}
+
+type (
+	// TrimmedLowerString is a string trimmed and lowercased.
+	TrimmedLowerString = Normalized[TrimmedLowerPolicy]
+	// SlugString is a string turned into a URL slug.
+	SlugString = Normalized[SlugPolicy]
+	// EmailString is an email address made canonical by EmailNormalizer.
+	EmailString = Normalized[EmailPolicy]
+)
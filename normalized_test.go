@@ -0,0 +1,106 @@
+package textn8r
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var (
+	_ sql.Scanner              = (*EmailString)(nil)
+	_ driver.Valuer            = EmailString("")
+	_ encoding.TextUnmarshaler = (*SlugString)(nil)
+	_ json.Unmarshaler         = (*SlugString)(nil)
+	_ fmt.Stringer             = TrimmedLowerString("")
+)
+
+func TestNormalized(t *testing.T) {
+	var user struct {
+		Email EmailString        `json:"email"`
+		Slug  SlugString         `json:"slug"`
+		Name  TrimmedLowerString `json:"name"`
+		Tags  map[SlugString]int `json:"tags"`
+		Nick  *TrimmedLowerString
+	}
+	input := `{"email": " Jane@Example.COM ", "slug": "Crème Brûlée", "name": " JANE ", "tags": {"Go Lang": 1}, "Nick": " X "}`
+	if err := json.Unmarshal([]byte(input), &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "Jane@example.com" || user.Slug != "creme-brulee" || user.Name != "jane" || *user.Nick != "x" {
+		t.Errorf("json.Unmarshal() = %+v", user)
+	}
+	if user.Tags["go-lang"] != 1 {
+		t.Errorf("json.Unmarshal() map keys = %v; want go-lang", user.Tags)
+	}
+
+	if err := json.Unmarshal([]byte(`{"email": null}`), &user); err != nil || user.Email != "Jane@example.com" {
+		t.Errorf("json.Unmarshal() of null = %q, %v; want the value unchanged", user.Email, err)
+	}
+
+	err := json.Unmarshal([]byte(`{"email": "nobody"}`), &user)
+	var invalid *InvalidInputError
+	if !errors.As(err, &invalid) || user.Email != "Jane@example.com" {
+		t.Errorf("json.Unmarshal() of an invalid email = %q, %v; want an *InvalidInputError", user.Email, err)
+	}
+	if err := json.Unmarshal([]byte(`{"email": 1}`), &user); err == nil {
+		t.Error("json.Unmarshal() of a number succeeded")
+	}
+}
+
+func TestNormalizedSQL(t *testing.T) {
+	tests := []struct {
+		src      any
+		expected string
+		err      string
+	}{
+		{" A@B.C ", "A@b.c", ""},
+		{[]byte("X@Y"), "X@y", ""},
+		{"a@b@c", "", `textn8r: invalid input "a@b@c": not an email address: expected single address, got "@c"`},
+		{"a b@c d", "", `textn8r: invalid input "a b@c d": not an email address: no angle-addr`},
+		{nil, "", `textn8r: normalizer "normalized": cannot scan NULL`},
+		{int64(1), "", `textn8r: normalizer "normalized": cannot scan a column of type int64`},
+	}
+
+	for _, tt := range tests {
+		var email EmailString
+		err := email.Scan(tt.src)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Scan(%v) error = %v; want %s", tt.src, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || email.String() != tt.expected {
+			t.Errorf("Scan(%v) = %q, %v; want %q", tt.src, email, err, tt.expected)
+		}
+	}
+
+	var nullable sql.Null[EmailString]
+	if err := nullable.Scan(" A@B "); err != nil || !nullable.Valid || nullable.V != "A@b" {
+		t.Errorf("sql.Null Scan() = %+v, %v; want A@b", nullable, err)
+	}
+
+	value, err := EmailString(" A@B ").Value()
+	if err != nil || value != "A@b" {
+		t.Errorf("Value() = %v, %v; want A@b", value, err)
+	}
+	if _, err := EmailString("").Value(); err == nil {
+		t.Error("Value() of an empty email succeeded")
+	}
+}
+
+func TestNewNormalized(t *testing.T) {
+	slug, err := NewNormalized[SlugPolicy]("Hello, World!")
+	if err != nil || slug != "hello-world" {
+		t.Errorf("NewNormalized() = %q, %v; want hello-world", slug, err)
+	}
+
+	var text TrimmedLowerString
+	if err := text.UnmarshalText([]byte(" ABC ")); err != nil || text != "abc" {
+		t.Errorf("UnmarshalText() = %q, %v; want abc", text, err)
+	}
+}
@@ -56,8 +56,9 @@
 package textn8r
 
 import (
-	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Normalizers is a collection of normalizers.
@@ -97,6 +98,10 @@ func TrimSpaceNormalizer(input string) string {
 
 // RemoveExtraSpaceNormalizer removes extra white spaces from the input string.
 func RemoveExtraSpaceNormalizer(input string) string {
+	if !hasExtraSpace(input) {
+		return input
+	}
+
 	return strings.Join(strings.Fields(input), " ")
 }
 
@@ -107,172 +112,251 @@ func RemoveAllSpaceNormalizer(input string) string {
 
 // RemoveCarriageReturnNormalizer removes carriage return characters from the input string.
 func RemoveCarriageReturnNormalizer(input string) string {
-	regex := regexp.MustCompile(`\r`)
-	return regex.ReplaceAllString(input, "")
+	return strings.ReplaceAll(input, "\r", "")
 }
 
 // RemoveNewLineNormalizer removes new line characters from the input string.
 func RemoveNewLineNormalizer(input string) string {
-	regex := regexp.MustCompile(`\n`)
-	return regex.ReplaceAllString(input, "")
+	return strings.ReplaceAll(input, "\n", "")
 }
 
 // RemoveTabNormalizer removes tab characters from the input string.
 func RemoveTabNormalizer(input string) string {
-	regex := regexp.MustCompile(`\t`)
-	return regex.ReplaceAllString(input, "")
+	return strings.ReplaceAll(input, "\t", "")
 }
 
 // RemoveNonAlphanumericNormalizer removes non-alphanumeric characters from the input string.
 func RemoveNonAlphanumericNormalizer(input string) string {
-	regex := regexp.MustCompile(`[^a-zA-Z0-9]+`)
-	return regex.ReplaceAllString(input, "")
+	return replaceRunes(input, isNotASCIIAlphanumeric, "", false)
 }
 
 // RemoveTildesNormalizer removes tildes from the input string.
 func RemoveTildesNormalizer(input string) string {
-	regex := regexp.MustCompile(`[~]`)
-	return regex.ReplaceAllString(input, "")
+	return strings.ReplaceAll(input, "~", "")
 }
 
 // RemoveDiacriticsNormalizer removes diacritics from the input string.
 func RemoveDiacriticsNormalizer(input string) string {
-	regex := regexp.MustCompile(`[^\x00-\x7F]+`)
-	return regex.ReplaceAllString(input, "")
+	return replaceRunes(input, isNotASCII, "", false)
 }
 
 // RemoveSpecialCharactersNormalizer removes special characters from the input string.
 func RemoveSpecialCharactersNormalizer(input string) string {
-	regex := regexp.MustCompile(`[^a-zA-Z0-9\s\p{L}\p{N}]+`)
-	return regex.ReplaceAllString(input, " ")
+	return replaceRunes(input, isSpecialCharacter, " ", true)
 }
 
 // RemovePunctuationNormalizer removes punctuation characters from the input string.
 func RemovePunctuationNormalizer(input string) string {
-	regex := regexp.MustCompile(`[[:punct:]]`)
-	input = regex.ReplaceAllString(input, "")
-
-	// not removed: ¿ ! ¡
-	regex = regexp.MustCompile(`[¿!¡]`)
-	input = regex.ReplaceAllString(input, "")
-
-	return input
+	return replaceRunes(input, isRemovedPunctuation, "", false)
 }
 
 // RemoveDigitsNormalizer removes digit characters from the input string.
 func RemoveDigitsNormalizer(input string) string {
-	regex := regexp.MustCompile(`[0-9]`)
-	return regex.ReplaceAllString(input, "")
+	return replaceRunes(input, isASCIIDigit, "", false)
 }
 
 // ReplaceSpecialCharactersNormalizer replaces special characters with a given replacement string.
 func ReplaceSpecialCharactersNormalizer(input, replacement string) string {
-	regex := regexp.MustCompile(`[^a-zA-Z0-9\s\p{L}\p{N}]+`)
-	return regex.ReplaceAllString(input, replacement)
+	return replaceRunes(input, isSpecialCharacter, replacement, true)
 }
 
 // ReplaceAccentsNormalizer replaces accented characters with their non-accented counterparts.
+// Every combining mark is stripped from Latin, Greek and Cyrillic letters, whether the input is
+// precomposed or decomposed, and letters without a decomposition such as "ł", "đ" or "ø" are
+// mapped to their base letter. Characters of other scripts are left untouched.
 func ReplaceAccentsNormalizer(input string) string {
-	// lowercase
-	regex := regexp.MustCompile(`[áàãâä]`)
-	input = regex.ReplaceAllString(input, "a")
-	regex = regexp.MustCompile(`[éèêë]`)
-	input = regex.ReplaceAllString(input, "e")
-	regex = regexp.MustCompile(`[íìîï]`)
-	input = regex.ReplaceAllString(input, "i")
-	regex = regexp.MustCompile(`[óòõôö]`)
-	input = regex.ReplaceAllString(input, "o")
-	regex = regexp.MustCompile(`[úùûü]`)
-	input = regex.ReplaceAllString(input, "u")
-	regex = regexp.MustCompile(`[ç]`)
-	input = regex.ReplaceAllString(input, "c")
-	regex = regexp.MustCompile(`[ñ]`)
-	input = regex.ReplaceAllString(input, "n")
-	// uppercase
-	regex = regexp.MustCompile(`[ÁÀÃÂÄ]`)
-	input = regex.ReplaceAllString(input, "A")
-	regex = regexp.MustCompile(`[ÉÈÊË]`)
-	input = regex.ReplaceAllString(input, "E")
-	regex = regexp.MustCompile(`[ÍÌÎÏ]`)
-	input = regex.ReplaceAllString(input, "I")
-	regex = regexp.MustCompile(`[ÓÒÕÔÖ]`)
-	input = regex.ReplaceAllString(input, "O")
-	regex = regexp.MustCompile(`[ÚÙÛÜ]`)
-	input = regex.ReplaceAllString(input, "U")
-	regex = regexp.MustCompile(`[Ç]`)
-	input = regex.ReplaceAllString(input, "C")
-	regex = regexp.MustCompile(`[Ñ]`)
-	input = regex.ReplaceAllString(input, "N")
-
-	return input
+	return foldAccents(input)
 }
 
 // ReplaceTildesNormalizer replaces tildes with their non-tilde counterparts.
 func ReplaceTildesNormalizer(input string) string {
-	regex := regexp.MustCompile(`[ñ]`)
-	return regex.ReplaceAllString(input, "n")
+	return strings.ReplaceAll(input, "ñ", "n")
 }
 
 // ReplaceTabNormalizer replaces tab characters with a given replacement string.
 func ReplaceTabNormalizer(replacement string) Normalizer {
-	return func(input string) string {
-		regex := regexp.MustCompile(`\t`)
-		return regex.ReplaceAllString(input, replacement)
-	}
+	return replaceNormalizer(isRune('\t'), replacement, false)
 }
 
 // ReplaceCarriageReturnNormalizer replaces carriage return characters with a given replacement string.
 func ReplaceCarriageReturnNormalizer(replacement string) Normalizer {
-	return func(input string) string {
-		regex := regexp.MustCompile(`\r`)
-		return regex.ReplaceAllString(input, replacement)
-	}
+	return replaceNormalizer(isRune('\r'), replacement, false)
 }
 
 // ReplaceNonAlphanumericNormalizer replaces non-alphanumeric characters with a given replacement string.
 func ReplaceNonAlphanumericNormalizer(replacement string) Normalizer {
-	return func(input string) string {
-		regex := regexp.MustCompile(`[^a-zA-Z0-9]+`)
-		return regex.ReplaceAllString(input, replacement)
-	}
+	return replaceNormalizer(isNotASCIIAlphanumeric, replacement, true)
 }
 
 // ReplacePunctuationNormalizer replaces punctuation characters with a given replacement string.
 func ReplacePunctuationNormalizer(replacement string) Normalizer {
-	return func(input string) string {
-		regex := regexp.MustCompile(`[[:punct:]]`)
-		return regex.ReplaceAllString(input, replacement)
-	}
+	return replaceNormalizer(isASCIIPunctuation, replacement, false)
 }
 
 // ReplaceDigitsNormalizer replaces digit characters with a given replacement string.
 func ReplaceDigitsNormalizer(replacement string) Normalizer {
-	return func(input string) string {
-		regex := regexp.MustCompile(`[0-9]`)
-		return regex.ReplaceAllString(input, replacement)
-	}
+	return replaceNormalizer(isASCIIDigit, replacement, false)
 }
 
 // ReplaceSpaceNormalizer replaces space characters with a given replacement string.
 func ReplaceSpaceNormalizer(replacement string) Normalizer {
-	return func(input string) string {
-		regex := regexp.MustCompile(`\s`)
-		return regex.ReplaceAllString(input, replacement)
-	}
+	return replaceNormalizer(isASCIISpace, replacement, false)
 }
 
 // ReplaceDiacriticsNormalizer replaces diacritics with a given replacement string.
 func ReplaceDiacriticsNormalizer(replacement string) Normalizer {
-	return func(input string) string {
-		regex := regexp.MustCompile(`[^\x00-\x7F]+`)
-		return regex.ReplaceAllString(input, replacement)
-	}
+	return replaceNormalizer(isNotASCII, replacement, true)
 }
 
 // ReplaceNewLineNormalizer replaces new line characters with a given replacement string.
 func ReplaceNewLineNormalizer(replacement string) Normalizer {
+	return replaceNormalizer(isRune('\n'), replacement, false)
+}
+
+// replaceSpecialCharactersNormalizer is ReplaceSpecialCharactersNormalizer as
+// a constructor, like the other Replace normalizers.
+func replaceSpecialCharactersNormalizer(replacement string) Normalizer {
+	return replaceNormalizer(isSpecialCharacter, replacement, true)
+}
+
+// replaceNormalizer returns a normalizer working like replaceRunes, described
+// as a step that Compile can fuse.
+func replaceNormalizer(match func(rune) bool, replacement string, runs bool) Normalizer {
+	d := &descriptor{
+		normalize: func(input string) string {
+			return replaceRunes(input, match, replacement, runs)
+		},
+		scope:   ScopeStream,
+		step:    runeStep{kind: stepReplace, match: match, replacement: replacement, runs: runs},
+		fusible: true,
+	}
+	if runs {
+		d.runs = match
+	}
+
+	return d.normalizer()
+}
+
+// CollapseNormalizer replaces every run of consecutive repetitions of s with a
+// single s, so with "-" the input "a---b" becomes "a-b".
+func CollapseNormalizer(s string) Normalizer {
+	double := s + s
 	return func(input string) string {
-		regex := regexp.MustCompile(`\n`)
-		return regex.ReplaceAllString(input, replacement)
+		if s == "" || !strings.Contains(input, double) {
+			return input
+		}
+
+		var b strings.Builder
+		b.Grow(len(input))
+		for {
+			i := strings.Index(input, double)
+			if i < 0 {
+				break
+			}
+			b.WriteString(input[:i+len(s)])
+			input = input[i+len(s):]
+			for strings.HasPrefix(input, s) {
+				input = input[len(s):]
+			}
+		}
+		b.WriteString(input)
+
+		return b.String()
+	}
+}
+
+// replaceRunes replaces every rune for which match is true with the
+// replacement, or every run of such runes when runs is set. Invalid UTF-8
+// bytes are matched as utf8.RuneError, one byte at a time. The input is
+// returned as it is, without allocating, when no rune matches.
+func replaceRunes(input string, match func(rune) bool, replacement string, runs bool) string {
+	i := 0
+	for i < len(input) {
+		r, size := rune(input[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(input[i:])
+		}
+		if match(r) {
+			break
+		}
+		i += size
+	}
+	if i == len(input) {
+		return input
+	}
+
+	var b strings.Builder
+	b.Grow(len(input))
+	b.WriteString(input[:i])
+	matching := false
+	for i < len(input) {
+		r, size := rune(input[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(input[i:])
+		}
+		switch {
+		case !match(r):
+			b.WriteString(input[i : i+size])
+			matching = false
+		case !runs || !matching:
+			b.WriteString(replacement)
+			matching = true
+		}
+		i += size
+	}
+
+	return b.String()
+}
+
+// hasExtraSpace reports whether strings.Fields would not give back the input
+// when joined with single spaces.
+func hasExtraSpace(input string) bool {
+	prevSpace := true
+	for _, r := range input {
+		space := unicode.IsSpace(r)
+		if space && (prevSpace || r != ' ') {
+			return true
+		}
+		prevSpace = space
+	}
+
+	return prevSpace && input != ""
+}
+
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isNotASCII(r rune) bool {
+	return r >= utf8.RuneSelf
+}
+
+func isNotASCIIAlphanumeric(r rune) bool {
+	return r >= utf8.RuneSelf || !isASCIIAlphanumeric(byte(r))
+}
+
+// isASCIIPunctuation matches the [[:punct:]] class of regular expressions.
+func isASCIIPunctuation(r rune) bool {
+	return r >= '!' && r <= '/' || r >= ':' && r <= '@' || r >= '[' && r <= '`' || r >= '{' && r <= '~'
+}
+
+// isRemovedPunctuation matches the characters RemovePunctuationNormalizer
+// removes: [[:punct:]], and "¿" and "¡", which it does not hold.
+func isRemovedPunctuation(r rune) bool {
+	return isASCIIPunctuation(r) || r == '¿' || r == '¡'
+}
+
+// isASCIISpace matches the \s class of regular expressions.
+func isASCIISpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\f' || r == '\r'
+}
+
+// isSpecialCharacter reports whether r is neither a letter, a number nor an
+// ASCII space.
+func isSpecialCharacter(r rune) bool {
+	if r < utf8.RuneSelf {
+		return !isASCIIAlphanumeric(byte(r)) && !isASCIISpace(r)
 	}
+
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
 }
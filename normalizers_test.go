@@ -1,6 +1,7 @@
 package textn8r
 
 import (
+	"regexp"
 	"testing"
 )
 
@@ -67,6 +68,11 @@ func TestRemoveExtraSpaceNormalizer(t *testing.T) {
 		{"hello   world", "hello world"},
 		{"hello world", "hello world"},
 		{"  hello   world  ", "hello world"},
+		{"hello\tworld", "hello world"},
+		{"hello\u00a0world", "hello world"},
+		{"hello world ", "hello world"},
+		{" ", ""},
+		{"", ""},
 	}
 
 	for _, tt := range tests {
@@ -470,6 +476,30 @@ func TestReplaceNewLineNormalizer(t *testing.T) {
 	}
 }
 
+func TestCollapseNormalizer(t *testing.T) {
+	tests := []struct {
+		input    string
+		s        string
+		expected string
+	}{
+		{"a---b", "-", "a-b"},
+		{"--a-b--", "-", "-a-b-"},
+		{"a - - b", " -", "a - b"},
+		{"a - b", " -", "a - b"},
+		{"a -- -- b", " --", "a -- b"},
+		{"no runs", "-", "no runs"},
+		{"a--b", "", "a--b"},
+		{"ééé", "é", "é"},
+	}
+
+	for _, tt := range tests {
+		result := CollapseNormalizer(tt.s).Apply(tt.input)
+		if result != tt.expected {
+			t.Errorf("CollapseNormalizer(%q)(%q) = %q; want %q", tt.s, tt.input, result, tt.expected)
+		}
+	}
+}
+
 func TestNormalizersApply(t *testing.T) {
 	tests := []struct {
 		input       string
@@ -575,3 +605,123 @@ durante siglos.
 		t.Errorf("Normalizers.Apply()\n want:\n%q \n\ngot:\n%q", result, expectedText)
 	}
 }
+
+// builtinNormalizers lists every normalizer of normalizers.go, with an input
+// each of them leaves unchanged.
+var builtinNormalizers = []struct {
+	name       string
+	normalizer Normalizer
+	clean      string
+}{
+	{"UpperCaseNormalizer", UpperCaseNormalizer, "HELLO, WORLD 2023"},
+	{"LowerCaseNormalizer", LowerCaseNormalizer, "hello, world 2023"},
+	{"TrimSpaceNormalizer", TrimSpaceNormalizer, "Hello, World 2023"},
+	{"RemoveExtraSpaceNormalizer", RemoveExtraSpaceNormalizer, "Hello, World 2023"},
+	{"RemoveAllSpaceNormalizer", RemoveAllSpaceNormalizer, "Hello,World2023"},
+	{"RemoveCarriageReturnNormalizer", RemoveCarriageReturnNormalizer, "Hello, World 2023"},
+	{"RemoveNewLineNormalizer", RemoveNewLineNormalizer, "Hello, World 2023"},
+	{"RemoveTabNormalizer", RemoveTabNormalizer, "Hello, World 2023"},
+	{"RemoveNonAlphanumericNormalizer", RemoveNonAlphanumericNormalizer, "HelloWorld2023"},
+	{"RemoveTildesNormalizer", RemoveTildesNormalizer, "Hello, World 2023"},
+	{"RemoveDiacriticsNormalizer", RemoveDiacriticsNormalizer, "Hello, World 2023"},
+	{"RemoveSpecialCharactersNormalizer", RemoveSpecialCharactersNormalizer, "Héllo World 2023"},
+	{"RemovePunctuationNormalizer", RemovePunctuationNormalizer, "Hello World 2023"},
+	{"RemoveDigitsNormalizer", RemoveDigitsNormalizer, "Hello, World"},
+	{"ReplaceSpecialCharactersNormalizer", replaceSpecialCharactersNormalizer("-"), "Héllo World 2023"},
+	{"ReplaceAccentsNormalizer", ReplaceAccentsNormalizer, "Hello, World 2023"},
+	{"ReplaceTildesNormalizer", ReplaceTildesNormalizer, "Hello, World 2023"},
+	{"ReplaceTabNormalizer", ReplaceTabNormalizer("-"), "Hello, World 2023"},
+	{"ReplaceCarriageReturnNormalizer", ReplaceCarriageReturnNormalizer("-"), "Hello, World 2023"},
+	{"ReplaceNonAlphanumericNormalizer", ReplaceNonAlphanumericNormalizer("-"), "HelloWorld2023"},
+	{"ReplacePunctuationNormalizer", ReplacePunctuationNormalizer("-"), "Hello World 2023"},
+	{"ReplaceDigitsNormalizer", ReplaceDigitsNormalizer("-"), "Hello, World"},
+	{"ReplaceSpaceNormalizer", ReplaceSpaceNormalizer("-"), "Hello,World-2023"},
+	{"ReplaceDiacriticsNormalizer", ReplaceDiacriticsNormalizer("-"), "Hello, World 2023"},
+	{"ReplaceNewLineNormalizer", ReplaceNewLineNormalizer("-"), "Hello, World 2023"},
+}
+
+// benchmarkInput has something for every normalizer to change.
+const benchmarkInput = "  ¿Sabías que el número áureo,\tφ (phi), es ~1,618...?\r\n¡Es increíble!  Cañón @2023  "
+
+func TestNormalizersDoNotAllocateWhenUnchanged(t *testing.T) {
+	for _, tt := range builtinNormalizers {
+		if result := tt.normalizer(tt.clean); result != tt.clean {
+			t.Errorf("%s(%q) = %q; want it unchanged", tt.name, tt.clean, result)
+			continue
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = tt.normalizer(tt.clean)
+		})
+		if allocs != 0 {
+			t.Errorf("%s(%q) allocated %v times; want 0", tt.name, tt.clean, allocs)
+		}
+	}
+}
+
+func BenchmarkNormalizers(b *testing.B) {
+	for _, bb := range builtinNormalizers {
+		b.Run(bb.name+"/changed", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(benchmarkInput)))
+			for i := 0; i < b.N; i++ {
+				_ = bb.normalizer(benchmarkInput)
+			}
+		})
+		b.Run(bb.name+"/unchanged", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(bb.clean)))
+			for i := 0; i < b.N; i++ {
+				_ = bb.normalizer(bb.clean)
+			}
+		})
+	}
+}
+
+// TestNormalizersMatchRegexp checks the rune scanners against the regular
+// expressions they replace.
+func TestNormalizersMatchRegexp(t *testing.T) {
+	regexps := []struct {
+		name        string
+		normalizer  Normalizer
+		pattern     string
+		replacement string
+	}{
+		{"RemoveCarriageReturnNormalizer", RemoveCarriageReturnNormalizer, `\r`, ""},
+		{"RemoveNewLineNormalizer", RemoveNewLineNormalizer, `\n`, ""},
+		{"RemoveTabNormalizer", RemoveTabNormalizer, `\t`, ""},
+		{"RemoveNonAlphanumericNormalizer", RemoveNonAlphanumericNormalizer, `[^a-zA-Z0-9]+`, ""},
+		{"RemoveTildesNormalizer", RemoveTildesNormalizer, `[~]`, ""},
+		{"RemoveDiacriticsNormalizer", RemoveDiacriticsNormalizer, `[^\x00-\x7F]+`, ""},
+		{"RemoveSpecialCharactersNormalizer", RemoveSpecialCharactersNormalizer, `[^a-zA-Z0-9\s\p{L}\p{N}]+`, " "},
+		{"RemovePunctuationNormalizer", RemovePunctuationNormalizer, `[[:punct:]¿!¡]`, ""},
+		{"RemoveDigitsNormalizer", RemoveDigitsNormalizer, `[0-9]`, ""},
+		{"ReplaceTildesNormalizer", ReplaceTildesNormalizer, `[ñ]`, "n"},
+		{"ReplaceTabNormalizer", ReplaceTabNormalizer("-"), `\t`, "-"},
+		{"ReplaceCarriageReturnNormalizer", ReplaceCarriageReturnNormalizer("-"), `\r`, "-"},
+		{"ReplaceNonAlphanumericNormalizer", ReplaceNonAlphanumericNormalizer("-"), `[^a-zA-Z0-9]+`, "-"},
+		{"ReplacePunctuationNormalizer", ReplacePunctuationNormalizer("-"), `[[:punct:]]`, "-"},
+		{"ReplaceDigitsNormalizer", ReplaceDigitsNormalizer("-"), `[0-9]`, "-"},
+		{"ReplaceSpaceNormalizer", ReplaceSpaceNormalizer("-"), `\s`, "-"},
+		{"ReplaceDiacriticsNormalizer", ReplaceDiacriticsNormalizer("-"), `[^\x00-\x7F]+`, "-"},
+		{"ReplaceNewLineNormalizer", ReplaceNewLineNormalizer("-"), `\n`, "-"},
+	}
+	inputs := []string{
+		"",
+		benchmarkInput,
+		"Hello, World!",
+		"tab\tnew\nline\rfeed\fvertical\vnbsp\u00a0end",
+		"invalid \xff\xfe bytes \xe2\x82 and \uFFFD",
+		"¿¡!?~ñÑ ① ² ٣ x²",
+		"___---...",
+	}
+
+	for _, rr := range regexps {
+		re := regexp.MustCompile(rr.pattern)
+		for _, input := range inputs {
+			expected := re.ReplaceAllString(input, rr.replacement)
+			if result := rr.normalizer(input); result != expected {
+				t.Errorf("%s(%+q) = %+q; want %+q", rr.name, input, result, expected)
+			}
+		}
+	}
+}
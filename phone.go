@@ -0,0 +1,430 @@
+package textn8r
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// phoneFile holds the numbering plans of the known regions.
+//
+//go:embed phones.txt
+var phoneFile string
+
+// phonePlan is the numbering plan of a region.
+type phonePlan struct {
+	region string
+	code   string
+	// idd is the international call prefix dialled from the region.
+	idd string
+	// trunk is the national prefix, empty when there is none.
+	trunk string
+	// minLength and maxLength bound the length of national significant
+	// numbers.
+	minLength, maxLength int
+	// patterns are the display patterns, checked in order. Numbers no
+	// pattern fits are written without grouping.
+	patterns []phonePattern
+}
+
+// phonePattern is a display pattern of the numbers starting with leading,
+// where '#' stands for a digit.
+type phonePattern struct {
+	leading                 string
+	national, international string
+}
+
+var (
+	// phoneRegions holds the plans by region code.
+	phoneRegions = map[string]*phonePlan{}
+	// phoneCodes holds the plan of the main region of each calling code.
+	phoneCodes = map[string]*phonePlan{}
+)
+
+func init() {
+	plans, err := parsePhonePlans(strings.NewReader(phoneFile))
+	if err != nil {
+		panic("textn8r: phones.txt: " + err.Error())
+	}
+	for _, p := range plans {
+		phoneRegions[p.region] = p
+		if _, ok := phoneCodes[p.code]; !ok {
+			phoneCodes[p.code] = p
+		}
+	}
+}
+
+// parsePhonePlans reads numbering plans in the format of phones.txt.
+func parsePhonePlans(r io.Reader) ([]*phonePlan, error) {
+	var plans []*phonePlan
+	regions := map[string]*phonePlan{}
+	// The patterns of the plan lines are checked after those of the
+	// pattern lines.
+	defaults := map[*phonePlan]phonePattern{}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 4 {
+			p, ok := regions[fields[0]]
+			if !ok {
+				return nil, fmt.Errorf("line %d: pattern of unknown region %q", line, fields[0])
+			}
+			pattern, err := p.parsePattern(fields[1], fields[2], fields[3])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			p.patterns = append(p.patterns, pattern)
+			continue
+		}
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("line %d: want 7 fields, or 4 for a pattern, got %q", line, text)
+		}
+		p := &phonePlan{region: fields[0], code: fields[1], idd: fields[2], trunk: fields[3]}
+		if p.trunk == "-" {
+			p.trunk = ""
+		}
+		minLength, maxLength, ok := strings.Cut(fields[4], "-")
+		if !ok {
+			maxLength = minLength
+		}
+		var err error
+		if p.minLength, err = strconv.Atoi(minLength); err != nil {
+			return nil, fmt.Errorf("line %d: invalid lengths %q", line, fields[4])
+		}
+		if p.maxLength, err = strconv.Atoi(maxLength); err != nil || p.maxLength < p.minLength {
+			return nil, fmt.Errorf("line %d: invalid lengths %q", line, fields[4])
+		}
+		if fields[5] != "-" || fields[6] != "-" {
+			pattern, err := p.parsePattern("-", fields[5], fields[6])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			defaults[p] = pattern
+		}
+		plans = append(plans, p)
+		regions[p.region] = p
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, p := range plans {
+		if pattern, ok := defaults[p]; ok {
+			p.patterns = append(p.patterns, pattern)
+		}
+	}
+
+	return plans, nil
+}
+
+// parsePattern reads the fields of a display pattern, "-" standing for no
+// leading digits.
+func (p *phonePlan) parsePattern(leading, national, international string) (phonePattern, error) {
+	if leading == "-" {
+		leading = ""
+	}
+	if strings.Trim(leading, "0123456789#") != "" {
+		return phonePattern{}, fmt.Errorf("invalid leading digits %q", leading)
+	}
+
+	n := strings.Count(national, "#")
+	for _, pattern := range []string{national, international} {
+		if c := strings.Count(pattern, "#"); c != n || c < p.minLength || c > p.maxLength || c < len(leading) {
+			return phonePattern{}, fmt.Errorf("pattern %q does not fit the lengths", pattern)
+		}
+	}
+
+	return phonePattern{
+		leading:       leading,
+		national:      strings.ReplaceAll(national, "_", " "),
+		international: strings.ReplaceAll(international, "_", " "),
+	}, nil
+}
+
+// pattern returns the first pattern fitting the national significant number.
+func (p *phonePlan) pattern(nsn string) (phonePattern, bool) {
+	for _, pattern := range p.patterns {
+		if strings.Count(pattern.national, "#") != len(nsn) || len(pattern.leading) > len(nsn) {
+			continue
+		}
+		matches := true
+		for i := 0; i < len(pattern.leading); i++ {
+			if pattern.leading[i] != '#' && pattern.leading[i] != nsn[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return pattern, true
+		}
+	}
+
+	return phonePattern{}, false
+}
+
+// PhoneFormat selects how PhoneNumber.Format writes numbers.
+type PhoneFormat int
+
+const (
+	// PhoneE164 writes numbers as "+34612345678", and extensions as
+	// ";ext=12" as in tel URIs.
+	PhoneE164 PhoneFormat = iota
+	// PhoneInternational writes numbers as "+34 612 34 56 78", and
+	// extensions as " ext. 12". The digits are grouped by the pattern of
+	// the region and the leading digits, if phones.txt has one, and are
+	// written together otherwise.
+	PhoneInternational
+	// PhoneNational writes numbers as dialled within their region, such as
+	// "(555) 123-4567" or "020 7946 0958", and extensions as " ext. 12".
+	// The digits are grouped as for PhoneInternational.
+	PhoneNational
+)
+
+// String returns the name of the format.
+func (f PhoneFormat) String() string {
+	switch f {
+	case PhoneE164:
+		return "e164"
+	case PhoneInternational:
+		return "international"
+	case PhoneNational:
+		return "national"
+	}
+
+	return "PhoneFormat(" + strconv.Itoa(int(f)) + ")"
+}
+
+// PhoneNumber is a parsed phone number.
+type PhoneNumber struct {
+	// CountryCode is the country calling code, such as "34".
+	CountryCode string
+	// Region is the region code of the number: the default region when it
+	// shares the calling code, or else the main region of the code.
+	Region string
+	// Number is the national significant number, without the national
+	// prefix.
+	Number string
+	// Extension holds the digits of the extension, if any.
+	Extension string
+}
+
+// ParsePhone parses a phone number written in national or international
+// format. National numbers are read in the numbering plan of defaultRegion,
+// an ISO 3166 region code such as "US" or "ES"; without one, only numbers
+// starting with "+" or "00" and a calling code are accepted. Spaces, dots,
+// dashes, slashes and parentheses are ignored, and extensions are
+// introduced by "ext", "x", "#" or ";ext=".
+//
+// Numbers that cannot be valid give an *InvalidInputError, and unknown
+// regions an *ArgumentError.
+func ParsePhone(input, defaultRegion string) (*PhoneNumber, error) {
+	var plan *phonePlan
+	if defaultRegion != "" {
+		var ok bool
+		if plan, ok = phoneRegions[strings.ToUpper(defaultRegion)]; !ok {
+			return nil, &ArgumentError{Normalizer: "phone", Param: "region", Reason: "unknown region " + strconv.Quote(defaultRegion)}
+		}
+	}
+	invalid := func(reason string) (*PhoneNumber, error) {
+		return nil, &InvalidInputError{Input: input, Reason: reason}
+	}
+
+	number, extension := splitPhoneExtension(input)
+	digits, international, err := phoneDigits(number)
+	if err != nil {
+		return invalid(err.Error())
+	}
+
+	switch {
+	case international:
+	case plan != nil && strings.HasPrefix(digits, plan.idd):
+		digits, international = digits[len(plan.idd):], true
+	case plan == nil && strings.HasPrefix(digits, "00"):
+		digits, international = digits[2:], true
+	case plan == nil:
+		return invalid("no country calling code and no default region")
+	}
+
+	var nsn string
+	if international {
+		// The calling code is matched on its own: "+34" alone is too short.
+		matched := false
+		for n := 1; n <= 3 && n <= len(digits); n++ {
+			if p, ok := phoneCodes[digits[:n]]; ok {
+				plan, nsn, matched = p, digits[n:], true
+				break
+			}
+		}
+		if !matched {
+			if len(digits) > 3 {
+				digits = digits[:3]
+			}
+			return invalid("unknown country calling code +" + digits)
+		}
+		// Numbers written as "+44 (0)20 7946 0958" keep the national prefix.
+		if plan.trunk != "" && strings.HasPrefix(nsn, plan.trunk) && !plan.fits(nsn) && plan.fits(nsn[len(plan.trunk):]) {
+			nsn = nsn[len(plan.trunk):]
+		}
+	} else {
+		nsn = digits
+		if plan.trunk != "" && strings.HasPrefix(nsn, plan.trunk) {
+			if stripped := nsn[len(plan.trunk):]; plan.fits(stripped) || !plan.fits(nsn) {
+				nsn = stripped
+			}
+		}
+	}
+
+	switch {
+	case len(nsn) < plan.minLength:
+		return invalid("too short for +" + plan.code)
+	case len(nsn) > plan.maxLength:
+		return invalid("too long for +" + plan.code)
+	}
+
+	region := plan.region
+	if defaultRegion != "" && phoneRegions[strings.ToUpper(defaultRegion)].code == plan.code {
+		region = strings.ToUpper(defaultRegion)
+	}
+
+	return &PhoneNumber{CountryCode: plan.code, Region: region, Number: nsn, Extension: extension}, nil
+}
+
+// fits reports whether a national significant number has a valid length.
+func (p *phonePlan) fits(nsn string) bool {
+	return len(nsn) >= p.minLength && len(nsn) <= p.maxLength
+}
+
+// phoneExtensionMarkers introduce extensions, longest first.
+var phoneExtensionMarkers = []string{";ext=", "extension", "ext.", "ext", "x", "#"}
+
+// splitPhoneExtension splits the extension from the number. Markers are
+// matched regardless of case.
+func splitPhoneExtension(input string) (number, extension string) {
+	lower := []byte(input)
+	for i, c := range lower {
+		if c >= 'A' && c <= 'Z' {
+			lower[i] = c + 'a' - 'A'
+		}
+	}
+
+	for _, marker := range phoneExtensionMarkers {
+		i := strings.LastIndex(string(lower), marker)
+		if i <= 0 {
+			continue
+		}
+		ext := strings.TrimSpace(input[i+len(marker):])
+		if ext == "" || len(ext) > 10 || strings.Trim(ext, "0123456789") != "" {
+			continue
+		}
+		return input[:i], ext
+	}
+
+	return input, ""
+}
+
+// phoneDigits returns the digits of a number and whether it starts with "+".
+func phoneDigits(number string) (string, bool, error) {
+	var digits []byte
+	plus := false
+	for _, r := range strings.TrimSpace(number) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, byte(r))
+		case r == '+' && len(digits) == 0 && !plus:
+			plus = true
+		case strings.ContainsRune("  .-/()‐‑‒–—−", r):
+		default:
+			return "", false, errors.New("invalid character " + strconv.QuoteRune(r))
+		}
+	}
+	if len(digits) == 0 {
+		return "", false, errors.New("no digits")
+	}
+
+	return string(digits), plus, nil
+}
+
+// E164 returns the number in E.164 format, such as "+34612345678", without
+// the extension.
+func (n *PhoneNumber) E164() string {
+	return "+" + n.CountryCode + n.Number
+}
+
+// Format returns the number in the format.
+func (n *PhoneNumber) Format(format PhoneFormat) string {
+	plan := phoneRegions[n.Region]
+	if plan == nil {
+		plan = phoneCodes[n.CountryCode]
+	}
+
+	pattern, ok := plan.pattern(n.Number)
+	var s string
+	switch format {
+	case PhoneInternational:
+		if ok {
+			s = "+" + n.CountryCode + " " + fillPhonePattern(pattern.international, n.Number)
+		} else {
+			s = "+" + n.CountryCode + " " + n.Number
+		}
+	case PhoneNational:
+		if ok {
+			s = fillPhonePattern(pattern.national, n.Number)
+		} else {
+			s = plan.trunk + n.Number
+		}
+	default:
+		if n.Extension != "" {
+			return n.E164() + ";ext=" + n.Extension
+		}
+		return n.E164()
+	}
+
+	if n.Extension != "" {
+		s += " ext. " + n.Extension
+	}
+
+	return s
+}
+
+// fillPhonePattern replaces the '#' of the pattern with the digits.
+func fillPhonePattern(pattern, digits string) string {
+	out := []byte(pattern)
+	j := 0
+	for i, c := range out {
+		if c == '#' {
+			out[i] = digits[j]
+			j++
+		}
+	}
+
+	return string(out)
+}
+
+// PhoneNormalizer returns a normalizer writing phone numbers in E.164
+// format, reading national numbers in the numbering plan of defaultRegion.
+// See ParsePhone for the accepted input.
+func PhoneNormalizer(defaultRegion string) NormalizerE {
+	return PhoneFormatNormalizer(defaultRegion, PhoneE164)
+}
+
+// PhoneFormatNormalizer returns a normalizer writing phone numbers in the
+// format, reading national numbers in the numbering plan of defaultRegion.
+func PhoneFormatNormalizer(defaultRegion string, format PhoneFormat) NormalizerE {
+	return func(input string) (string, error) {
+		n, err := ParsePhone(input, defaultRegion)
+		if err != nil {
+			return input, err
+		}
+		return n.Format(format), nil
+	}
+}
@@ -0,0 +1,180 @@
+package textn8r
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		input    string
+		region   string
+		expected PhoneNumber
+	}{
+		{"(555) 123-4567", "US", PhoneNumber{"1", "US", "5551234567", ""}},
+		{"1-555-123-4567", "US", PhoneNumber{"1", "US", "5551234567", ""}},
+		{"+1 416 555 0123", "CA", PhoneNumber{"1", "CA", "4165550123", ""}},
+		{"011 34 612 34 56 78", "US", PhoneNumber{"34", "ES", "612345678", ""}},
+		{"+34 612 34 56 78", "", PhoneNumber{"34", "ES", "612345678", ""}},
+		{"0034612345678", "", PhoneNumber{"34", "ES", "612345678", ""}},
+		{"0034612345678", "ES", PhoneNumber{"34", "ES", "612345678", ""}},
+		{"612.34.56.78", "es", PhoneNumber{"34", "ES", "612345678", ""}},
+		{"020 7946 0958", "GB", PhoneNumber{"44", "GB", "2079460958", ""}},
+		{"+44 (0)20 7946 0958", "US", PhoneNumber{"44", "GB", "2079460958", ""}},
+		{"06 12 34 56 78", "FR", PhoneNumber{"33", "FR", "612345678", ""}},
+		{"06 1 234 5678", "HU", PhoneNumber{"36", "HU", "12345678", ""}},
+		{"8 (495) 123-45-67", "RU", PhoneNumber{"7", "RU", "4951234567", ""}},
+		{"+7 727 123 4567", "KZ", PhoneNumber{"7", "KZ", "7271234567", ""}},
+		{"06 1234 5678", "IT", PhoneNumber{"39", "IT", "0612345678", ""}},
+		{"+39 06 1234 5678", "", PhoneNumber{"39", "IT", "0612345678", ""}},
+		{"(555) 123-4567 ext. 89", "US", PhoneNumber{"1", "US", "5551234567", "89"}},
+		{"555-123-4567x89", "US", PhoneNumber{"1", "US", "5551234567", "89"}},
+		{"+1 555 123 4567 #12", "", PhoneNumber{"1", "US", "5551234567", "12"}},
+		{"+15551234567;ext=3", "", PhoneNumber{"1", "US", "5551234567", "3"}},
+		{"555–123–4567", "US", PhoneNumber{"1", "US", "5551234567", ""}},
+	}
+
+	for _, tt := range tests {
+		n, err := ParsePhone(tt.input, tt.region)
+		if err != nil {
+			t.Errorf("ParsePhone(%q, %q) error = %v", tt.input, tt.region, err)
+			continue
+		}
+		if *n != tt.expected {
+			t.Errorf("ParsePhone(%q, %q) = %+v; want %+v", tt.input, tt.region, *n, tt.expected)
+		}
+	}
+}
+
+func TestParsePhoneErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		region   string
+		expected string
+	}{
+		{"", "US", `textn8r: invalid input "": no digits`},
+		{"call me", "US", `textn8r: invalid input "call me": invalid character 'c'`},
+		{"1-800-FLOWERS", "US", `textn8r: invalid input "1-800-FLOWERS": invalid character 'F'`},
+		{"5+55", "US", `textn8r: invalid input "5+55": invalid character '+'`},
+		{"555 1234", "", `textn8r: invalid input "555 1234": no country calling code and no default region`},
+		{"555 1234", "US", `textn8r: invalid input "555 1234": too short for +1`},
+		{"+34 612 34 56 78 9", "", `textn8r: invalid input "+34 612 34 56 78 9": too long for +34`},
+		{"+999 1234 5678", "", `textn8r: invalid input "+999 1234 5678": unknown country calling code +999`},
+		{"+", "", `textn8r: invalid input "+": no digits`},
+		{"+34", "", `textn8r: invalid input "+34": too short for +34`},
+		{"011 34", "US", `textn8r: invalid input "011 34": too short for +34`},
+	}
+
+	for _, tt := range tests {
+		_, err := ParsePhone(tt.input, tt.region)
+		var invalid *InvalidInputError
+		if !errors.As(err, &invalid) || err.Error() != tt.expected {
+			t.Errorf("ParsePhone(%q, %q) error = %v; want %s", tt.input, tt.region, err, tt.expected)
+		}
+	}
+
+	_, err := ParsePhone("555 1234", "XX")
+	var argErr *ArgumentError
+	if !errors.As(err, &argErr) || argErr.Param != "region" {
+		t.Errorf("ParsePhone() with an unknown region error = %v; want an *ArgumentError", err)
+	}
+}
+
+func TestPhoneFormat(t *testing.T) {
+	tests := []struct {
+		input         string
+		region        string
+		e164          string
+		international string
+		national      string
+	}{
+		{"(555) 123-4567", "US", "+15551234567", "+1 555-123-4567", "(555) 123-4567"},
+		{"+34612345678", "ES", "+34612345678", "+34 612 34 56 78", "612 34 56 78"},
+		{"07700 900123", "GB", "+447700900123", "+44 7700 900123", "07700 900123"},
+		{"020 7946 0958", "GB", "+442079460958", "+44 20 7946 0958", "020 7946 0958"},
+		{"0121 496 0000", "GB", "+441214960000", "+44 121 496 0000", "0121 496 0000"},
+		{"0612345678", "FR", "+33612345678", "+33 6 12 34 56 78", "06 12 34 56 78"},
+		{"030 1234567", "DE", "+49301234567", "+49 30 1234567", "030 1234567"},
+		{"0151 12345678", "DE", "+4915112345678", "+49 151 12345678", "0151 12345678"},
+		{"02 1234 5678", "AU", "+61212345678", "+61 2 1234 5678", "02 1234 5678"},
+		{"0412 345 678", "AU", "+61412345678", "+61 412 345 678", "0412 345 678"},
+		{"010 1234 5678", "CN", "+861012345678", "+86 10 1234 5678", "010 1234 5678"},
+		{"138 0013 8000", "CN", "+8613800138000", "+86 138 0013 8000", "138 0013 8000"},
+		// Numbers without a pattern are written without grouping.
+		{"0511 1234567", "DE", "+495111234567", "+49 5111234567", "05111234567"},
+		{"555 123 4567 x12", "US", "+15551234567;ext=12", "+1 555-123-4567 ext. 12", "(555) 123-4567 ext. 12"},
+	}
+
+	for _, tt := range tests {
+		n, err := ParsePhone(tt.input, tt.region)
+		if err != nil {
+			t.Errorf("ParsePhone(%q, %q) error = %v", tt.input, tt.region, err)
+			continue
+		}
+		for format, expected := range map[PhoneFormat]string{PhoneE164: tt.e164, PhoneInternational: tt.international, PhoneNational: tt.national} {
+			if result := n.Format(format); result != expected {
+				t.Errorf("Format(%v) of %q = %q; want %q", format, tt.input, result, expected)
+			}
+			// Formatted numbers parse back to the same number.
+			again, err := ParsePhone(expected, tt.region)
+			if err != nil || *again != *n {
+				t.Errorf("ParsePhone(%q, %q) = %+v, %v; want %+v", expected, tt.region, again, err, *n)
+			}
+		}
+	}
+}
+
+func TestPhoneNormalizer(t *testing.T) {
+	n := PhoneNormalizer("ES")
+	for input, expected := range map[string]string{
+		"612 34 56 78":      "+34612345678",
+		"+34 612 34 56 78":  "+34612345678",
+		"0034612345678":     "+34612345678",
+		"+1 (555) 123-4567": "+15551234567",
+	} {
+		if result, err := n(input); err != nil || result != expected {
+			t.Errorf("PhoneNormalizer(ES)(%q) = %q, %v; want %q", input, result, err, expected)
+		}
+	}
+
+	if result, err := n("12"); err == nil || result != "12" {
+		t.Errorf("PhoneNormalizer(ES)(12) = %q, %v; want the input and an error", result, err)
+	}
+
+	national, err := Build("phone", Args{"region": "us", "format": "National"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, err := national("+1 555 123 4567"); err != nil || result != "(555) 123-4567" {
+		t.Errorf("phone(national) = %q, %v; want (555) 123-4567", result, err)
+	}
+	for _, args := range []Args{{"region": "XX"}, {"format": "local"}} {
+		if _, err := Build("phone", args); err == nil {
+			t.Errorf("Build(phone, %v) succeeded", args)
+		}
+	}
+}
+
+func TestParsePhonePlans(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"US 1 011 1 10", "line 1: want 7 fields"},
+		{"US 1 011 1 x - -", `line 1: invalid lengths "x"`},
+		{"US 1 011 1 10-9 - -", `line 1: invalid lengths "10-9"`},
+		{"US 1 011 1 10 ### -", `line 1: pattern "###" does not fit the lengths`},
+		{"US 1 011 1 10 ########## -", `line 1: pattern "-" does not fit the lengths`},
+		{"GB 2 0##_####_#### ##_####_####", `line 1: pattern of unknown region "GB"`},
+		{"GB 44 00 0 9-10 - -\nGB 2x 0##_####_#### ##_####_####", `line 2: invalid leading digits "2x"`},
+		{"GB 44 00 0 9-10 - -\nGB 2 0##_####_#### #_####_####", `line 2: pattern "#_####_####" does not fit the lengths`},
+	}
+
+	for _, tt := range tests {
+		_, err := parsePhonePlans(strings.NewReader(tt.input))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.expected) {
+			t.Errorf("parsePhonePlans(%q) error = %v; want %s", tt.input, err, tt.expected)
+		}
+	}
+}
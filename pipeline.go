@@ -0,0 +1,181 @@
+package textn8r
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NormalizerE is a normalizer that can reject its input, such as one parsing
+// email addresses or phone numbers.
+type NormalizerE func(input string) (string, error)
+
+// Apply applies the normalizer to the input string.
+func (n NormalizerE) Apply(input string) (string, error) {
+	return n(input)
+}
+
+// NormalizerE returns the normalizer as a NormalizerE that never fails.
+func (n Normalizer) NormalizerE() NormalizerE {
+	return func(input string) (string, error) {
+		return n(input), nil
+	}
+}
+
+// NormalizerE returns the normalizers as a NormalizerE that never fails.
+func (n Normalizers) NormalizerE() NormalizerE {
+	return Normalizer(n.Apply).NormalizerE()
+}
+
+// InvalidInputError is returned by normalizers that reject their input.
+type InvalidInputError struct {
+	Input string
+	// Reason tells what is wrong with the input.
+	Reason string
+}
+
+func (e *InvalidInputError) Error() string {
+	return "textn8r: invalid input " + strconv.Quote(e.Input) + ": " + e.Reason
+}
+
+// StepError reports the failure of a step of a Pipeline.
+type StepError struct {
+	// Step is the name of the step, or its position when it has no name.
+	Step string
+	// Index is the position of the step in the pipeline.
+	Index int
+	// Input is the input the step rejected.
+	Input string
+	Err   error
+}
+
+func (e *StepError) Error() string {
+	return "textn8r: " + e.message()
+}
+
+func (e *StepError) message() string {
+	return "step " + strconv.Quote(e.Step) + ": " + strings.TrimPrefix(e.Err.Error(), "textn8r: ")
+}
+
+// Unwrap returns the error of the step.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// PipelineError holds the errors of every failed step of a Pipeline in
+// CollectErrors mode.
+type PipelineError struct {
+	Errors []*StepError
+}
+
+func (e *PipelineError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.message()
+	}
+
+	return "textn8r: " + strconv.Itoa(len(e.Errors)) + " steps failed: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors of the steps, so errors.As and errors.Is look into
+// each of them.
+func (e *PipelineError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// ErrorMode selects what a Pipeline does when a step fails.
+type ErrorMode int
+
+const (
+	// StopOnError stops at the first failed step and returns its StepError.
+	StopOnError ErrorMode = iota
+	// CollectErrors skips failed steps, leaving their input unchanged, runs
+	// the remaining ones and returns a PipelineError holding every failure.
+	CollectErrors
+)
+
+// String returns the name of the mode.
+func (m ErrorMode) String() string {
+	switch m {
+	case StopOnError:
+		return "stop"
+	case CollectErrors:
+		return "collect"
+	}
+
+	return "ErrorMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// Step is a named step of a Pipeline.
+type Step struct {
+	Name       string
+	Normalizer NormalizerE
+}
+
+// Pipeline runs steps that can fail in order. The zero value is an empty
+// pipeline that stops on the first error.
+type Pipeline struct {
+	Steps []Step
+	Mode  ErrorMode
+}
+
+// NewPipeline returns a pipeline of the steps that stops on the first error.
+func NewPipeline(steps ...Step) *Pipeline {
+	return &Pipeline{Steps: steps}
+}
+
+// Add appends a step that cannot fail and returns the pipeline.
+func (p *Pipeline) Add(name string, n Normalizer) *Pipeline {
+	return p.AddE(name, n.NormalizerE())
+}
+
+// AddE appends a step and returns the pipeline.
+func (p *Pipeline) AddE(name string, n NormalizerE) *Pipeline {
+	p.Steps = append(p.Steps, Step{Name: name, Normalizer: n})
+	return p
+}
+
+// Apply runs the steps on the input string. In StopOnError mode it returns
+// the input of the failed step along with its *StepError. In CollectErrors
+// mode it returns the output of the steps that did not fail along with a
+// *PipelineError, or a nil error when every step succeeded.
+func (p *Pipeline) Apply(input string) (string, error) {
+	var failed []*StepError
+	for i, step := range p.Steps {
+		output, err := step.Normalizer(input)
+		if err == nil {
+			input = output
+			continue
+		}
+
+		name := step.Name
+		if name == "" {
+			name = "#" + strconv.Itoa(i)
+		}
+		stepErr := &StepError{Step: name, Index: i, Input: input, Err: err}
+		if p.Mode != CollectErrors {
+			return input, stepErr
+		}
+		failed = append(failed, stepErr)
+	}
+
+	if len(failed) > 0 {
+		return input, &PipelineError{Errors: failed}
+	}
+
+	return input, nil
+}
+
+// NormalizerE returns the Apply method as a NormalizerE, so a pipeline can be
+// a step of another one.
+func (p *Pipeline) NormalizerE() NormalizerE {
+	return p.Apply
+}
@@ -0,0 +1,150 @@
+package textn8r
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// requireAt fails on input without an "@".
+func requireAt(input string) (string, error) {
+	if !strings.Contains(input, "@") {
+		return input, &InvalidInputError{Input: input, Reason: `missing "@"`}
+	}
+	return input, nil
+}
+
+// requireDot fails on input without a ".".
+func requireDot(input string) (string, error) {
+	if !strings.Contains(input, ".") {
+		return input, &InvalidInputError{Input: input, Reason: `missing "."`}
+	}
+	return input, nil
+}
+
+func TestNormalizerNormalizerE(t *testing.T) {
+	result, err := Normalizer(TrimSpaceNormalizer).NormalizerE().Apply("  hi  ")
+	if result != "hi" || err != nil {
+		t.Errorf("NormalizerE()(%q) = %q, %v; want %q, nil", "  hi  ", result, err, "hi")
+	}
+}
+
+func TestNormalizersNormalizerE(t *testing.T) {
+	n := Normalizers{TrimSpaceNormalizer, UpperCaseNormalizer}.NormalizerE()
+	result, err := n("  hi  ")
+	if result != "HI" || err != nil {
+		t.Errorf("NormalizerE()(%q) = %q, %v; want %q, nil", "  hi  ", result, err, "HI")
+	}
+}
+
+func TestPipelineApply(t *testing.T) {
+	p := NewPipeline().
+		Add("trim", TrimSpaceNormalizer).
+		AddE("require_at", requireAt).
+		Add("lower", LowerCaseNormalizer).
+		AddE("require_dot", requireDot)
+
+	tests := []struct {
+		input    string
+		expected string
+		step     string
+	}{
+		{"  Jane@Example.COM ", "jane@example.com", ""},
+		{"  Jane.Example.COM ", "Jane.Example.COM", "require_at"},
+		{" Jane@Example ", "jane@example", "require_dot"},
+	}
+
+	for _, tt := range tests {
+		result, err := p.Apply(tt.input)
+		if result != tt.expected {
+			t.Errorf("Apply(%q) = %q; want %q", tt.input, result, tt.expected)
+		}
+		if tt.step == "" {
+			if err != nil {
+				t.Errorf("Apply(%q) error = %v; want nil", tt.input, err)
+			}
+			continue
+		}
+
+		var stepErr *StepError
+		if !errors.As(err, &stepErr) {
+			t.Errorf("Apply(%q) error = %v; want a *StepError", tt.input, err)
+			continue
+		}
+		if stepErr.Step != tt.step {
+			t.Errorf("Apply(%q) failed at %q; want %q", tt.input, stepErr.Step, tt.step)
+		}
+		var invalid *InvalidInputError
+		if !errors.As(err, &invalid) || invalid.Input != stepErr.Input {
+			t.Errorf("Apply(%q) error = %v; want an *InvalidInputError for %q", tt.input, err, stepErr.Input)
+		}
+	}
+}
+
+func TestPipelineCollectErrors(t *testing.T) {
+	p := &Pipeline{
+		Steps: []Step{
+			{Name: "require_at", Normalizer: requireAt},
+			{Normalizer: Normalizer(UpperCaseNormalizer).NormalizerE()},
+			{Name: "require_dot", Normalizer: requireDot},
+		},
+		Mode: CollectErrors,
+	}
+
+	result, err := p.Apply("jane")
+	if result != "JANE" {
+		t.Errorf("Apply(%q) = %q; want %q", "jane", result, "JANE")
+	}
+
+	var pipelineErr *PipelineError
+	if !errors.As(err, &pipelineErr) {
+		t.Fatalf("Apply(%q) error = %v; want a *PipelineError", "jane", err)
+	}
+	if len(pipelineErr.Errors) != 2 {
+		t.Fatalf("Apply(%q) collected %d errors; want 2", "jane", len(pipelineErr.Errors))
+	}
+	if e := pipelineErr.Errors[1]; e.Step != "require_dot" || e.Index != 2 || e.Input != "JANE" {
+		t.Errorf("second error = %+v; want step require_dot at 2 on %q", e, "JANE")
+	}
+
+	expected := `textn8r: 2 steps failed: step "require_at": invalid input "jane": missing "@"; ` +
+		`step "require_dot": invalid input "JANE": missing "."`
+	if err.Error() != expected {
+		t.Errorf("Error() = %q; want %q", err.Error(), expected)
+	}
+
+	var invalid *InvalidInputError
+	if !errors.As(err, &invalid) || invalid.Input != "jane" {
+		t.Errorf("errors.As(%v) did not find the first *InvalidInputError", err)
+	}
+
+	if _, err := p.Apply("jane@example.com"); err != nil {
+		t.Errorf("Apply(%q) error = %v; want nil", "jane@example.com", err)
+	}
+}
+
+func TestPipelineUnnamedStep(t *testing.T) {
+	_, err := NewPipeline(Step{Normalizer: requireAt}).Apply("jane")
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "#0" {
+		t.Errorf("Apply error = %v; want a *StepError for step #0", err)
+	}
+}
+
+func TestErrorModeString(t *testing.T) {
+	tests := []struct {
+		mode     ErrorMode
+		expected string
+	}{
+		{StopOnError, "stop"},
+		{CollectErrors, "collect"},
+		{ErrorMode(7), "ErrorMode(7)"},
+	}
+
+	for _, tt := range tests {
+		if result := tt.mode.String(); result != tt.expected {
+			t.Errorf("ErrorMode(%d).String() = %q; want %q", int(tt.mode), result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package textn8r
+
+// raceEnabled is set under the race detector, which makes sync.Pool drop
+// some of the values put back, so pooled buffers are allocated again.
+const raceEnabled = true
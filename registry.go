@@ -0,0 +1,699 @@
+package textn8r
+
+import (
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Category groups related normalizers in the registry.
+type Category string
+
+// Categories of the built-in normalizers.
+const (
+	CategoryCase            Category = "case"
+	CategoryIdentifier      Category = "identifier"
+	CategorySpace           Category = "space"
+	CategoryRemoval         Category = "removal"
+	CategoryReplacement     Category = "replacement"
+	CategoryAccents         Category = "accents"
+	CategoryUnicode         Category = "unicode"
+	CategoryTransliteration Category = "transliteration"
+	CategoryValidation      Category = "validation"
+	CategoryCustom          Category = "custom"
+	// CategoryPipeline holds the pipelines added with RegisterPipeline.
+	CategoryPipeline Category = "pipeline"
+)
+
+// ParamType is the type of a parameter of a registered normalizer.
+type ParamType int
+
+const (
+	// ParamString parameters hold a string.
+	ParamString ParamType = iota
+	// ParamInt parameters hold an int.
+	ParamInt
+	// ParamBool parameters hold a bool.
+	ParamBool
+	// ParamStrings parameters hold a []string.
+	ParamStrings
+)
+
+// String returns the name of the type.
+func (t ParamType) String() string {
+	switch t {
+	case ParamString:
+		return "string"
+	case ParamInt:
+		return "int"
+	case ParamBool:
+		return "bool"
+	case ParamStrings:
+		return "strings"
+	}
+
+	return "ParamType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Param describes a parameter of a registered normalizer.
+type Param struct {
+	Name        string
+	Type        ParamType
+	Description string
+	// Required parameters must be given. Others default to Default, or to the
+	// zero value of their type when Default is nil.
+	Required bool
+	Default  any
+}
+
+// Args holds the arguments of a registered normalizer by parameter name.
+// Arguments are strings, ints, bools or string slices, as the parameter types
+// say.
+type Args map[string]any
+
+// String returns the argument as a string, or "" when it is not one.
+func (a Args) String(name string) string {
+	s, _ := a[name].(string)
+	return s
+}
+
+// Int returns the argument as an int, or 0 when it is not one.
+func (a Args) Int(name string) int {
+	i, _ := a[name].(int)
+	return i
+}
+
+// Bool returns the argument as a bool, or false when it is not one.
+func (a Args) Bool(name string) bool {
+	b, _ := a[name].(bool)
+	return b
+}
+
+// Strings returns the argument as a string slice, or nil when it is not one.
+func (a Args) Strings(name string) []string {
+	s, _ := a[name].([]string)
+	return s
+}
+
+// Definition describes a normalizer of the registry and builds it.
+type Definition struct {
+	// Name is the stable name of the normalizer, made of lowercase ASCII
+	// letters, digits and underscores, such as "replace_tab".
+	Name        string
+	Description string
+	Category    Category
+	Params      []Param
+	// Idempotent is set when applying the normalizer twice gives the same
+	// result as applying it once, for arguments that do not bring back what
+	// it removes.
+	Idempotent bool
+	// ASCIIOnly is set when the normalizer only recognizes ASCII characters,
+	// leaving other characters alone or handling them all alike.
+	ASCIIOnly bool
+	// New builds the normalizer from arguments checked against Params. It
+	// returns an error for values its parameter type allows but it does not.
+	New func(args Args) (Normalizer, error)
+	// NewE builds normalizers that can reject their input, for definitions
+	// without New.
+	NewE func(args Args) (NormalizerE, error)
+
+	builtin bool
+}
+
+// Fallible reports whether the normalizer can reject its input.
+func (d Definition) Fallible() bool {
+	return d.New == nil
+}
+
+// Build checks the arguments and builds the normalizer.
+func (d Definition) Build(args Args) (NormalizerE, error) {
+	checked, err := d.check(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.New == nil {
+		return d.NewE(checked)
+	}
+	n, err := d.New(checked)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.NormalizerE(), nil
+}
+
+// BuildNormalizer checks the arguments and builds the normalizer as a
+// Normalizer, which keeps built-in normalizers recognizable by Compile,
+// AppendNormalize and the streaming Reader and Writer. The normalizer
+// remembers its name and arguments, so FormatNormalizers writes it back. It
+// fails for normalizers that can reject their input.
+func (d Definition) BuildNormalizer(args Args) (Normalizer, error) {
+	if d.New == nil {
+		return nil, &ArgumentError{Normalizer: d.Name, Reason: "can reject its input and must be built with Build"}
+	}
+	checked, err := d.check(args)
+	if err != nil {
+		return nil, err
+	}
+	n, err := d.New(checked)
+	if err != nil {
+		return nil, err
+	}
+
+	return withSpec(n, StepSpec{Name: d.Name, Args: checked}), nil
+}
+
+// check returns the arguments converted to the parameter types, with the
+// defaults of missing ones.
+func (d Definition) check(args Args) (Args, error) {
+	for name := range args {
+		if d.paramIndex(name) < 0 {
+			return nil, &ArgumentError{Normalizer: d.Name, Param: name, Reason: "unknown parameter"}
+		}
+	}
+
+	checked := Args{}
+	for _, p := range d.Params {
+		value, ok := args[p.Name]
+		if !ok {
+			if p.Required {
+				return nil, &ArgumentError{Normalizer: d.Name, Param: p.Name, Reason: "missing required " + p.Type.String()}
+			}
+			value = p.Default
+			if value == nil {
+				value = zeroValue(p.Type)
+			}
+		}
+
+		converted, ok := convertArg(value, p.Type)
+		if !ok {
+			return nil, &ArgumentError{Normalizer: d.Name, Param: p.Name, Reason: "want " + p.Type.String()}
+		}
+		checked[p.Name] = converted
+	}
+
+	return checked, nil
+}
+
+func (d Definition) paramIndex(name string) int {
+	for i, p := range d.Params {
+		if p.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func zeroValue(t ParamType) any {
+	switch t {
+	case ParamInt:
+		return 0
+	case ParamBool:
+		return false
+	case ParamStrings:
+		return []string(nil)
+	}
+
+	return ""
+}
+
+// convertArg converts an argument to the parameter type. Integral floats are
+// accepted as ints and slices of strings as []any, as decoded from JSON.
+func convertArg(value any, t ParamType) (any, bool) {
+	switch t {
+	case ParamString:
+		s, ok := value.(string)
+		return s, ok
+
+	case ParamInt:
+		switch v := value.(type) {
+		case int:
+			return v, true
+		case int64:
+			return int(v), int64(int(v)) == v
+		case float64:
+			return int(v), v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32
+		}
+
+	case ParamBool:
+		b, ok := value.(bool)
+		return b, ok
+
+	case ParamStrings:
+		switch v := value.(type) {
+		case []string:
+			return v, true
+		case []any:
+			s := make([]string, len(v))
+			for i, item := range v {
+				str, ok := item.(string)
+				if !ok {
+					return nil, false
+				}
+				s[i] = str
+			}
+			return s, true
+		}
+	}
+
+	return nil, false
+}
+
+// ArgumentError reports invalid arguments of a registered normalizer.
+type ArgumentError struct {
+	Normalizer string
+	// Param is the name of the invalid parameter, if the error is about one.
+	Param  string
+	Reason string
+}
+
+func (e *ArgumentError) Error() string {
+	if e.Param == "" {
+		return "textn8r: normalizer " + strconv.Quote(e.Normalizer) + ": " + e.Reason
+	}
+
+	return "textn8r: normalizer " + strconv.Quote(e.Normalizer) + ": parameter " + strconv.Quote(e.Param) + ": " + e.Reason
+}
+
+// UnknownNormalizerError reports a name missing from the registry.
+type UnknownNormalizerError struct {
+	Name string
+}
+
+func (e *UnknownNormalizerError) Error() string {
+	return "textn8r: unknown normalizer " + strconv.Quote(e.Name)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Definition{}
+	// registryGeneration counts the calls to Register, so that plans built
+	// from the registry know when they are stale.
+	registryGeneration atomic.Uint64
+)
+
+// Register adds the definition to the registry, replacing the one with the
+// same name if there is one, so built-in normalizers can be overridden.
+func Register(d Definition) error {
+	if !isRegistryName(d.Name) {
+		return &ArgumentError{Normalizer: d.Name, Reason: "name must be lowercase ASCII letters, digits and underscores"}
+	}
+	if (d.New == nil) == (d.NewE == nil) {
+		return &ArgumentError{Normalizer: d.Name, Reason: "exactly one of New and NewE must be set"}
+	}
+	for i, p := range d.Params {
+		if !isRegistryName(p.Name) || d.paramIndex(p.Name) != i {
+			return &ArgumentError{Normalizer: d.Name, Param: p.Name, Reason: "invalid or duplicate parameter name"}
+		}
+		if p.Default != nil {
+			if _, ok := convertArg(p.Default, p.Type); !ok {
+				return &ArgumentError{Normalizer: d.Name, Param: p.Name, Reason: "default value: want " + p.Type.String()}
+			}
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[d.Name] = d
+	registryGeneration.Add(1)
+
+	return nil
+}
+
+// RegisterPipeline registers a pipeline in the pipeline syntax under the
+// name, as a normalizer without parameters. It gives a name to pipelines
+// used in several places, such as struct tags read by Struct. The steps are
+// built when the pipeline is registered.
+func RegisterPipeline(name, src string) error {
+	spec, err := ParseSpec(src)
+	if err != nil {
+		return err
+	}
+
+	d := Definition{Name: name, Description: spec.String(), Category: CategoryPipeline}
+	if compiled, err := spec.Compile(); err == nil {
+		d.New = func(Args) (Normalizer, error) { return compiled, nil }
+	} else {
+		p, err := ParsePipeline(src)
+		if err != nil {
+			return err
+		}
+		d.NewE = func(Args) (NormalizerE, error) { return p.NormalizerE(), nil }
+	}
+
+	return Register(d)
+}
+
+// isRegistryName reports whether name is made of lowercase ASCII letters,
+// digits and underscores, and starts with a letter.
+func isRegistryName(name string) bool {
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if (c < 'a' || c > 'z') && !isASCIIDigit(rune(c)) && c != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Lookup returns the definition registered under the name.
+func Lookup(name string) (Definition, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	d, ok := registry[name]
+	return d, ok
+}
+
+// List returns every registered definition sorted by name.
+func List() []Definition {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	definitions := make([]Definition, 0, len(registry))
+	for _, d := range registry {
+		definitions = append(definitions, d)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+
+	return definitions
+}
+
+// Build builds the normalizer registered under the name with the arguments.
+func Build(name string, args Args) (NormalizerE, error) {
+	d, ok := Lookup(name)
+	if !ok {
+		return nil, &UnknownNormalizerError{Name: name}
+	}
+
+	return d.Build(args)
+}
+
+// BuildNormalizer builds the normalizer registered under the name with the
+// arguments as a Normalizer. It fails for normalizers that can reject their
+// input.
+func BuildNormalizer(name string, args Args) (Normalizer, error) {
+	d, ok := Lookup(name)
+	if !ok {
+		return nil, &UnknownNormalizerError{Name: name}
+	}
+
+	return d.BuildNormalizer(args)
+}
+
+func init() {
+	plain := func(n Normalizer) func(Args) (Normalizer, error) {
+		return func(Args) (Normalizer, error) { return n, nil }
+	}
+	replacing := func(newNormalizer func(string) Normalizer) func(Args) (Normalizer, error) {
+		return func(args Args) (Normalizer, error) {
+			return newNormalizer(args.String("replacement")), nil
+		}
+	}
+	replacement := []Param{{
+		Name:        "replacement",
+		Type:        ParamString,
+		Description: "string written in place of the removed characters",
+		Required:    true,
+	}}
+	locale := []Param{{
+		Name:        "locale",
+		Type:        ParamString,
+		Description: `BCP 47 language tag selecting language specific rules, such as "tr"`,
+	}}
+	exceptions := Param{
+		Name:        "exceptions",
+		Type:        ParamStrings,
+		Description: "words always written exactly as given",
+	}
+	initialisms := []Param{{
+		Name:        "initialisms",
+		Type:        ParamStrings,
+		Description: `words kept as given, such as "ID" or "HTTP"`,
+	}}
+	identifier := func(c IdentifierCase) func(Args) (Normalizer, error) {
+		return func(args Args) (Normalizer, error) {
+			if len(args.Strings("initialisms")) == 0 {
+				return identifierNormalizers[c], nil
+			}
+			return IdentifierCaseNormalizer(c, args.Strings("initialisms")...), nil
+		}
+	}
+
+	definitions := []Definition{
+		{Name: "upper", Description: "Converts to uppercase", Category: CategoryCase, Idempotent: true,
+			New: plain(UpperCaseNormalizer)},
+		{Name: "lower", Description: "Converts to lowercase", Category: CategoryCase, Idempotent: true,
+			New: plain(LowerCaseNormalizer)},
+		{Name: "case_fold", Description: "Applies full Unicode case folding for case-insensitive keys",
+			Category: CategoryCase, Idempotent: true, New: plain(CaseFoldNormalizer)},
+		{Name: "upper_locale", Description: "Converts to uppercase with full case mapping and the rules of the locale",
+			Category: CategoryCase, Params: locale, Idempotent: true,
+			New: func(args Args) (Normalizer, error) {
+				return UpperCaseLocaleNormalizer(Locale(args.String("locale"))), nil
+			}},
+		{Name: "lower_locale", Description: "Converts to lowercase with full case mapping and the rules of the locale",
+			Category: CategoryCase, Params: locale, Idempotent: true,
+			New: func(args Args) (Normalizer, error) {
+				return LowerCaseLocaleNormalizer(Locale(args.String("locale"))), nil
+			}},
+		{Name: "title_case", Description: "Converts to title case following a style guide",
+			Category: CategoryCase, Idempotent: true,
+			Params: []Param{
+				{Name: "style", Type: ParamString, Description: "style guide: ap, chicago, apa or spanish", Default: "ap"},
+				exceptions,
+			},
+			New: func(args Args) (Normalizer, error) {
+				style, ok := parseTitleCaseStyle(args.String("style"))
+				if !ok {
+					return nil, &ArgumentError{Normalizer: "title_case", Param: "style", Reason: "unknown style " + strconv.Quote(args.String("style"))}
+				}
+				return TitleCaseNormalizer(style, args.Strings("exceptions")...), nil
+			}},
+		{Name: "sentence_case", Description: "Capitalizes the first word of every sentence and lowercases the rest",
+			Category: CategoryCase, Params: []Param{exceptions}, Idempotent: true,
+			New: func(args Args) (Normalizer, error) {
+				return SentenceCaseNormalizer(args.Strings("exceptions")...), nil
+			}},
+
+		{Name: "camel_case", Description: "Converts to camelCase", Category: CategoryIdentifier,
+			Params: initialisms, Idempotent: true, New: identifier(CamelCase)},
+		{Name: "pascal_case", Description: "Converts to PascalCase", Category: CategoryIdentifier,
+			Params: initialisms, Idempotent: true, New: identifier(PascalCase)},
+		{Name: "snake_case", Description: "Converts to snake_case", Category: CategoryIdentifier,
+			Idempotent: true, New: identifier(SnakeCase)},
+		{Name: "kebab_case", Description: "Converts to kebab-case", Category: CategoryIdentifier,
+			Idempotent: true, New: identifier(KebabCase)},
+		{Name: "screaming_snake_case", Description: "Converts to SCREAMING_SNAKE_CASE", Category: CategoryIdentifier,
+			Idempotent: true, New: identifier(ScreamingSnakeCase)},
+		{Name: "dot_case", Description: "Converts to dot.case", Category: CategoryIdentifier,
+			Idempotent: true, New: identifier(DotCase)},
+		{Name: "train_case", Description: "Converts to Train-Case", Category: CategoryIdentifier,
+			Params: initialisms, Idempotent: true, New: identifier(TrainCase)},
+		{Name: "slug", Description: "Turns titles into URL slugs", Category: CategoryIdentifier, Idempotent: true,
+			Params: []Param{
+				{Name: "separator", Type: ParamString, Description: "string joining the words", Default: "-"},
+				{Name: "max_length", Type: ParamInt, Description: "maximum number of characters, 0 for no limit"},
+				{Name: "stop_words", Type: ParamStrings, Description: "words dropped from the slug"},
+				{Name: "locale", Type: ParamString, Description: "BCP 47 language tag selecting transliteration rules"},
+				{Name: "keep_unicode", Type: ParamBool, Description: "keep letters of every script instead of transliterating them"},
+			},
+			New: func(args Args) (Normalizer, error) {
+				if args.Int("max_length") < 0 {
+					return nil, &ArgumentError{Normalizer: "slug", Param: "max_length", Reason: "must not be negative"}
+				}
+				s := &Slugger{
+					Separator:   args.String("separator"),
+					MaxLength:   args.Int("max_length"),
+					StopWords:   args.Strings("stop_words"),
+					Locale:      Locale(args.String("locale")),
+					KeepUnicode: args.Bool("keep_unicode"),
+				}
+				return s.Normalizer(), nil
+			}},
+
+		{Name: "trim", Description: "Removes leading and trailing white space", Category: CategorySpace,
+			Idempotent: true, New: plain(TrimSpaceNormalizer)},
+		{Name: "remove_extra_space", Description: "Trims and collapses runs of white space into single spaces",
+			Category: CategorySpace, Idempotent: true, New: plain(RemoveExtraSpaceNormalizer)},
+		{Name: "remove_all_space", Description: "Removes all spaces", Category: CategorySpace,
+			Idempotent: true, ASCIIOnly: true, New: plain(RemoveAllSpaceNormalizer)},
+		{Name: "remove_carriage_return", Description: "Removes carriage returns", Category: CategorySpace,
+			Idempotent: true, ASCIIOnly: true, New: plain(RemoveCarriageReturnNormalizer)},
+		{Name: "remove_new_line", Description: "Removes line feeds", Category: CategorySpace,
+			Idempotent: true, ASCIIOnly: true, New: plain(RemoveNewLineNormalizer)},
+		{Name: "remove_tab", Description: "Removes tabs", Category: CategorySpace,
+			Idempotent: true, ASCIIOnly: true, New: plain(RemoveTabNormalizer)},
+
+		{Name: "remove_non_alphanumeric", Description: "Removes everything but ASCII letters and digits",
+			Category: CategoryRemoval, Idempotent: true, ASCIIOnly: true, New: plain(RemoveNonAlphanumericNormalizer)},
+		{Name: "remove_tildes", Description: `Removes "~"`, Category: CategoryRemoval,
+			Idempotent: true, ASCIIOnly: true, New: plain(RemoveTildesNormalizer)},
+		{Name: "remove_diacritics", Description: "Removes every non-ASCII character", Category: CategoryRemoval,
+			Idempotent: true, ASCIIOnly: true, New: plain(RemoveDiacriticsNormalizer)},
+		{Name: "remove_special_characters", Description: "Replaces runs of characters other than letters, numbers and spaces with a space",
+			Category: CategoryRemoval, Idempotent: true, New: plain(RemoveSpecialCharactersNormalizer)},
+		{Name: "remove_punctuation", Description: `Removes ASCII punctuation, "¿" and "¡"`, Category: CategoryRemoval,
+			Idempotent: true, ASCIIOnly: true, New: plain(RemovePunctuationNormalizer)},
+		{Name: "remove_digits", Description: "Removes ASCII digits", Category: CategoryRemoval,
+			Idempotent: true, ASCIIOnly: true, New: plain(RemoveDigitsNormalizer)},
+
+		{Name: "replace_special_characters", Description: "Replaces runs of characters other than letters, numbers and spaces",
+			Category: CategoryReplacement, Params: replacement, Idempotent: true,
+			New: replacing(replaceSpecialCharactersNormalizer)},
+		{Name: "replace_tab", Description: "Replaces tabs", Category: CategoryReplacement,
+			Params: replacement, Idempotent: true, ASCIIOnly: true, New: replacing(ReplaceTabNormalizer)},
+		{Name: "replace_carriage_return", Description: "Replaces carriage returns", Category: CategoryReplacement,
+			Params: replacement, Idempotent: true, ASCIIOnly: true, New: replacing(ReplaceCarriageReturnNormalizer)},
+		{Name: "replace_new_line", Description: "Replaces line feeds", Category: CategoryReplacement,
+			Params: replacement, Idempotent: true, ASCIIOnly: true, New: replacing(ReplaceNewLineNormalizer)},
+		{Name: "replace_non_alphanumeric", Description: "Replaces runs of characters other than ASCII letters and digits",
+			Category: CategoryReplacement, Params: replacement, Idempotent: true, ASCIIOnly: true,
+			New: replacing(ReplaceNonAlphanumericNormalizer)},
+		{Name: "replace_punctuation", Description: "Replaces ASCII punctuation", Category: CategoryReplacement,
+			Params: replacement, Idempotent: true, ASCIIOnly: true, New: replacing(ReplacePunctuationNormalizer)},
+		{Name: "replace_digits", Description: "Replaces ASCII digits", Category: CategoryReplacement,
+			Params: replacement, Idempotent: true, ASCIIOnly: true, New: replacing(ReplaceDigitsNormalizer)},
+		{Name: "replace_space", Description: "Replaces ASCII white space characters", Category: CategoryReplacement,
+			Params: replacement, Idempotent: true, ASCIIOnly: true, New: replacing(ReplaceSpaceNormalizer)},
+		{Name: "replace_diacritics", Description: "Replaces runs of non-ASCII characters", Category: CategoryReplacement,
+			Params: replacement, Idempotent: true, ASCIIOnly: true, New: replacing(ReplaceDiacriticsNormalizer)},
+
+		{Name: "collapse", Description: "Replaces runs of repetitions of a string with a single one",
+			Category: CategoryReplacement, Idempotent: true,
+			Params: []Param{{Name: "text", Type: ParamString, Description: "string whose repetitions are collapsed", Required: true}},
+			New: func(args Args) (Normalizer, error) {
+				return CollapseNormalizer(args.String("text")), nil
+			}},
+
+		{Name: "fold_accents", Description: "Replaces accented letters with their base letters", Category: CategoryAccents,
+			Idempotent: true, New: plain(ReplaceAccentsNormalizer)},
+		{Name: "replace_tildes", Description: `Replaces "ñ" with "n"`, Category: CategoryAccents,
+			Idempotent: true, New: plain(ReplaceTildesNormalizer)},
+
+		{Name: "nfc", Description: "Converts to Unicode normalization form C", Category: CategoryUnicode,
+			Idempotent: true, New: plain(NFCNormalizer)},
+		{Name: "nfd", Description: "Converts to Unicode normalization form D", Category: CategoryUnicode,
+			Idempotent: true, New: plain(NFDNormalizer)},
+		{Name: "nfkc", Description: "Converts to Unicode normalization form KC", Category: CategoryUnicode,
+			Idempotent: true, New: plain(NFKCNormalizer)},
+		{Name: "nfkd", Description: "Converts to Unicode normalization form KD", Category: CategoryUnicode,
+			Idempotent: true, New: plain(NFKDNormalizer)},
+
+		{Name: "transliterate", Description: "Folds letters the way the language of the locale expects",
+			Category: CategoryTransliteration, Params: locale, Idempotent: true,
+			New: func(args Args) (Normalizer, error) {
+				return TransliterateNormalizer(Locale(args.String("locale"))), nil
+			}},
+		{Name: "transliterate_ascii", Description: "Transliterates every script to ASCII",
+			Category: CategoryTransliteration, Idempotent: true,
+			Params: []Param{{Name: "fallback", Type: ParamString, Description: "string written for characters without an approximation"}},
+			New: func(args Args) (Normalizer, error) {
+				return TransliterateToASCIINormalizer(args.String("fallback")), nil
+			}},
+
+		{Name: "email", Description: "Parses an email address and makes it canonical", Category: CategoryValidation,
+			Idempotent: true,
+			Params: []Param{
+				{Name: "providers", Type: ParamBool, Description: "apply the rules of DefaultEmailProviders", Default: true},
+				{Name: "lower_local_part", Type: ParamBool, Description: "lowercase the local part of every address"},
+			},
+			NewE: func(args Args) (NormalizerE, error) {
+				options := EmailOptions{LowerLocalPart: args.Bool("lower_local_part")}
+				if args.Bool("providers") {
+					options.Providers = DefaultEmailProviders
+				}
+				return EmailNormalizer(options), nil
+			}},
+		{Name: "phone", Description: "Parses a phone number and writes it in E.164 or display format", Category: CategoryValidation,
+			Idempotent: true,
+			Params: []Param{
+				{Name: "region", Type: ParamString, Description: "ISO 3166 region code of national numbers, such as US"},
+				{Name: "format", Type: ParamString, Description: "e164, international or national", Default: "e164"},
+			},
+			NewE: func(args Args) (NormalizerE, error) {
+				region := args.String("region")
+				if _, ok := phoneRegions[strings.ToUpper(region)]; region != "" && !ok {
+					return nil, &ArgumentError{Normalizer: "phone", Param: "region", Reason: "unknown region " + strconv.Quote(region)}
+				}
+				format, ok := parsePhoneFormat(args.String("format"))
+				if !ok {
+					return nil, &ArgumentError{Normalizer: "phone", Param: "format", Reason: "unknown format " + strconv.Quote(args.String("format"))}
+				}
+				return PhoneFormatNormalizer(region, format), nil
+			}},
+		{Name: "url", Description: "Makes absolute URLs canonical following RFC 3986", Category: CategoryValidation,
+			Idempotent: true,
+			Params: []Param{
+				{Name: "sort_query", Type: ParamBool, Description: "sort the query parameters by name", Default: true},
+				{Name: "remove_params", Type: ParamStrings, Description: "globs of the query parameters to remove", Default: DefaultTrackingParams},
+				{Name: "remove_fragment", Type: ParamBool, Description: "remove the fragment", Default: true},
+			},
+			NewE: func(args Args) (NormalizerE, error) {
+				options := DefaultURLOptions
+				options.SortQuery = args.Bool("sort_query")
+				options.RemoveParams = args.Strings("remove_params")
+				options.RemoveFragment = args.Bool("remove_fragment")
+				for _, glob := range options.RemoveParams {
+					if _, err := path.Match(glob, ""); err != nil {
+						return nil, &ArgumentError{Normalizer: "url", Param: "remove_params", Reason: "invalid glob " + strconv.Quote(glob)}
+					}
+				}
+				return URLNormalizer(options), nil
+			}},
+	}
+
+	for _, d := range definitions {
+		d.builtin = true
+		if err := Register(d); err != nil {
+			panic(err)
+		}
+	}
+}
+
+var identifierNormalizers = map[IdentifierCase]Normalizer{
+	CamelCase:          CamelCaseNormalizer,
+	PascalCase:         PascalCaseNormalizer,
+	SnakeCase:          SnakeCaseNormalizer,
+	KebabCase:          KebabCaseNormalizer,
+	ScreamingSnakeCase: ScreamingSnakeCaseNormalizer,
+	DotCase:            DotCaseNormalizer,
+	TrainCase:          TrainCaseNormalizer,
+}
+
+// parsePhoneFormat returns the format named like its String method, in any
+// case.
+func parsePhoneFormat(name string) (PhoneFormat, bool) {
+	for _, format := range []PhoneFormat{PhoneE164, PhoneInternational, PhoneNational} {
+		if strings.EqualFold(format.String(), name) {
+			return format, true
+		}
+	}
+
+	return 0, false
+}
+
+// parseTitleCaseStyle returns the style named like its String method, in any
+// case.
+func parseTitleCaseStyle(name string) (TitleCaseStyle, bool) {
+	for _, style := range []TitleCaseStyle{TitleCaseAP, TitleCaseChicago, TitleCaseAPA, TitleCaseSpanish} {
+		if strings.EqualFold(style.String(), name) {
+			return style, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,226 @@
+package textn8r
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// registryArgs holds arguments for the definitions with required parameters.
+var registryArgs = map[string]Args{
+	"replace_special_characters": {"replacement": "-"},
+	"replace_tab":                {"replacement": "-"},
+	"replace_carriage_return":    {"replacement": "-"},
+	"replace_new_line":           {"replacement": "-"},
+	"replace_non_alphanumeric":   {"replacement": "-"},
+	"replace_punctuation":        {"replacement": "-"},
+	"replace_digits":             {"replacement": "-"},
+	"replace_space":              {"replacement": "-"},
+	"replace_diacritics":         {"replacement": "-"},
+	"collapse":                   {"text": "-"},
+}
+
+func TestRegistryBuiltins(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       Args
+		normalizer Normalizer
+	}{
+		{"trim", nil, TrimSpaceNormalizer},
+		{"lower", nil, LowerCaseNormalizer},
+		{"fold_accents", nil, ReplaceAccentsNormalizer},
+		{"replace_tab", Args{"replacement": "->"}, ReplaceTabNormalizer("->")},
+		{"lower_locale", Args{"locale": "tr"}, LowerCaseLocaleNormalizer(LocaleTurkish)},
+		{"title_case", Args{"style": "chicago"}, TitleCaseNormalizer(TitleCaseChicago)},
+		{"title_case", nil, TitleCaseNormalizer(TitleCaseAP)},
+		{"pascal_case", Args{"initialisms": []string{"ID"}}, IdentifierCaseNormalizer(PascalCase, "ID")},
+		{"slug", Args{"separator": "_", "max_length": 12}, (&Slugger{Separator: "_", MaxLength: 12}).Normalizer()},
+		{"transliterate_ascii", Args{"fallback": "?"}, TransliterateToASCIINormalizer("?")},
+	}
+
+	inputs := []string{benchmarkInput, "Ünïcode\tTÍTLE of the user id", "İSTANBUL ☃"}
+	for _, tt := range tests {
+		n, err := BuildNormalizer(tt.name, tt.args)
+		if err != nil {
+			t.Errorf("BuildNormalizer(%q, %v) error = %v", tt.name, tt.args, err)
+			continue
+		}
+		for _, input := range inputs {
+			if result, expected := n(input), tt.normalizer(input); result != expected {
+				t.Errorf("%s%v(%q) = %q; want %q", tt.name, tt.args, input, result, expected)
+			}
+		}
+	}
+}
+
+func TestRegistryKeepsBuiltinsRecognizable(t *testing.T) {
+	n, err := BuildNormalizer("replace_tab", Args{"replacement": "-"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := recognizeStep(n); !ok {
+		t.Errorf("replace_tab was not recognized by Compile")
+	}
+	if ScopeOf(n) != ScopeStream {
+		t.Errorf("ScopeOf(replace_tab) = %v; want %v", ScopeOf(n), ScopeStream)
+	}
+}
+
+func TestRegistryDefinitions(t *testing.T) {
+	definitions := List()
+	if len(definitions) == 0 {
+		t.Fatal("List() is empty")
+	}
+
+	inputs := []string{
+		"",
+		benchmarkInput,
+		"  Crème Brûlée\tAND the iPhone 15 — a review  ",
+		"HTTPServer_v2 user id",
+	}
+	for i, d := range definitions {
+		if i > 0 && definitions[i-1].Name >= d.Name {
+			t.Errorf("List() is not sorted: %q before %q", definitions[i-1].Name, d.Name)
+		}
+		if d.Description == "" || d.Category == "" {
+			t.Errorf("%s has no description or category", d.Name)
+		}
+		if found, ok := Lookup(d.Name); !ok || found.Name != d.Name {
+			t.Errorf("Lookup(%q) = %v, %v", d.Name, found.Name, ok)
+		}
+
+		n, err := d.Build(registryArgs[d.Name])
+		if err != nil {
+			t.Errorf("%s.Build(%v) error = %v", d.Name, registryArgs[d.Name], err)
+			continue
+		}
+		if !d.Idempotent {
+			continue
+		}
+		for _, input := range inputs {
+			once, _ := n(input)
+			twice, _ := n(once)
+			if once != twice {
+				t.Errorf("%s is idempotent but gives %q then %q on %q", d.Name, once, twice, input)
+			}
+		}
+	}
+}
+
+func TestRegistryErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   Args
+		param  string
+		reason string
+	}{
+		{"replace_tab", nil, "replacement", "missing required string"},
+		{"replace_tab", Args{"replacement": 1}, "replacement", "want string"},
+		{"trim", Args{"cutset": " "}, "cutset", "unknown parameter"},
+		{"slug", Args{"max_length": 1.5}, "max_length", "want int"},
+		{"slug", Args{"max_length": -1}, "max_length", "must not be negative"},
+		{"title_case", Args{"style": "mla"}, "style", `unknown style "mla"`},
+		{"title_case", Args{"exceptions": []any{"NASA", 1}}, "exceptions", "want strings"},
+	}
+
+	for _, tt := range tests {
+		_, err := Build(tt.name, tt.args)
+		var argErr *ArgumentError
+		if !errors.As(err, &argErr) {
+			t.Errorf("Build(%q, %v) error = %v; want an *ArgumentError", tt.name, tt.args, err)
+			continue
+		}
+		if argErr.Normalizer != tt.name || argErr.Param != tt.param || argErr.Reason != tt.reason {
+			t.Errorf("Build(%q, %v) error = %+v; want parameter %q: %s", tt.name, tt.args, argErr, tt.param, tt.reason)
+		}
+	}
+
+	_, err := Build("no_such_normalizer", nil)
+	var unknown *UnknownNormalizerError
+	if !errors.As(err, &unknown) || unknown.Name != "no_such_normalizer" {
+		t.Errorf("Build(%q) error = %v; want an *UnknownNormalizerError", "no_such_normalizer", err)
+	}
+	if err.Error() != `textn8r: unknown normalizer "no_such_normalizer"` {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestRegister(t *testing.T) {
+	err := Register(Definition{
+		Name:        "test_surround",
+		Description: "Surrounds the input",
+		Category:    CategoryCustom,
+		Params: []Param{
+			{Name: "left", Type: ParamString, Default: "["},
+			{Name: "right", Type: ParamString, Default: "]"},
+			{Name: "times", Type: ParamInt, Default: 1},
+		},
+		New: func(args Args) (Normalizer, error) {
+			left := strings.Repeat(args.String("left"), args.Int("times"))
+			right := strings.Repeat(args.String("right"), args.Int("times"))
+			return func(input string) string { return left + input + right }, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := BuildNormalizer("test_surround", Args{"left": "<", "times": float64(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := n("x"); result != "<<x]]" {
+		t.Errorf("test_surround(%q) = %q; want %q", "x", result, "<<x]]")
+	}
+
+	err = Register(Definition{
+		Name:     "test_reject",
+		Category: CategoryValidation,
+		NewE: func(Args) (NormalizerE, error) {
+			return func(input string) (string, error) {
+				return input, &InvalidInputError{Input: input, Reason: "always"}
+			}, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, _ := Lookup("test_reject")
+	if !d.Fallible() {
+		t.Errorf("test_reject is not Fallible")
+	}
+	if _, err := BuildNormalizer("test_reject", nil); err == nil {
+		t.Errorf("BuildNormalizer(%q) succeeded; want an error", "test_reject")
+	}
+	reject, err := Build("test_reject", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reject("x"); err == nil {
+		t.Errorf("test_reject(%q) succeeded; want an error", "x")
+	}
+
+	invalid := []Definition{
+		{Name: "", New: func(Args) (Normalizer, error) { return nil, nil }},
+		{Name: "Test-Name", New: func(Args) (Normalizer, error) { return nil, nil }},
+		{Name: "test_none"},
+		{Name: "test_param", Params: []Param{{Name: "a"}, {Name: "a"}}, New: func(Args) (Normalizer, error) { return nil, nil }},
+		{Name: "test_default", Params: []Param{{Name: "a", Type: ParamBool, Default: "yes"}}, New: func(Args) (Normalizer, error) { return nil, nil }},
+	}
+	for _, d := range invalid {
+		if err := Register(d); err == nil {
+			t.Errorf("Register(%q) succeeded; want an error", d.Name)
+		}
+	}
+}
+
+func TestArgs(t *testing.T) {
+	args := Args{"s": "x", "i": 3, "b": true, "l": []string{"a"}}
+	if args.String("s") != "x" || args.Int("i") != 3 || !args.Bool("b") || !reflect.DeepEqual(args.Strings("l"), []string{"a"}) {
+		t.Errorf("Args accessors returned wrong values for %v", args)
+	}
+	if args.String("i") != "" || args.Int("s") != 0 || args.Bool("missing") || args.Strings("s") != nil {
+		t.Errorf("Args accessors did not return zero values for mismatched types")
+	}
+}
@@ -0,0 +1,285 @@
+package textn8r
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSlugReplacements holds the symbols Slugger spells out by default.
+var DefaultSlugReplacements = map[string]string{
+	"&":  "and",
+	"@":  "at",
+	"+":  "plus",
+	"%":  "percent",
+	"C#": "C sharp",
+	"F#": "F sharp",
+}
+
+// SlugStore records the slugs in use so Slugger can keep them unique.
+type SlugStore interface {
+	// Claim records the slug as used and reports whether it was free.
+	Claim(slug string) bool
+}
+
+// MemorySlugStore is a SlugStore kept in memory. It is safe for concurrent
+// use and its zero value is ready to use.
+type MemorySlugStore struct {
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+// Claim records the slug as used and reports whether it was free.
+func (m *MemorySlugStore) Claim(slug string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.used[slug] {
+		return false
+	}
+	if m.used == nil {
+		m.used = map[string]bool{}
+	}
+	m.used[slug] = true
+
+	return true
+}
+
+// Slugger turns titles into URL slugs. The zero value makes lowercase ASCII
+// slugs of any length joined with "-", spelling out DefaultSlugReplacements.
+type Slugger struct {
+	// Separator joins the words of the slug. Empty means "-".
+	Separator string
+	// MaxLength is the maximum number of characters of the slug. The slug is
+	// cut at a word boundary unless its first word is longer. Zero means no limit.
+	MaxLength int
+	// Replacements maps symbols to the words written in their place. Nil means
+	// DefaultSlugReplacements.
+	Replacements map[string]string
+	// StopWords are dropped from the slug, unless every word is one.
+	StopWords []string
+	// Locale selects the transliteration rules of TransliterateNormalizer,
+	// so German "Müller" gives "mueller".
+	Locale Locale
+	// KeepUnicode keeps letters of every script instead of transliterating
+	// them to ASCII.
+	KeepUnicode bool
+	// Store, when set, makes slugs unique by appending "-2", "-3" and so on
+	// to slugs it already holds. The suffix counts toward MaxLength.
+	Store SlugStore
+	// MaxAttempts is the number of slugs offered to Store before giving up.
+	// Zero means DefaultSlugAttempts.
+	MaxAttempts int
+}
+
+// DefaultSlugAttempts is the number of slugs Slugger offers to its Store
+// when MaxAttempts is zero.
+const DefaultSlugAttempts = 1000
+
+// SlugError reports that Store held every slug Slugger tried.
+type SlugError struct {
+	// Slug is the slug without a suffix.
+	Slug     string
+	Attempts int
+}
+
+func (e *SlugError) Error() string {
+	return "textn8r: no free slug for " + strconv.Quote(e.Slug) + " after " + strconv.Itoa(e.Attempts) + " attempts"
+}
+
+// Normalizer returns the Slug method as a Normalizer, so the slugger can be
+// part of Normalizers.
+func (s *Slugger) Normalizer() Normalizer {
+	return s.Slug
+}
+
+// Slug returns the slug of the input string. When Store holds every slug
+// tried, Slug falls back to the slug without a suffix; use SlugE to detect
+// that.
+func (s *Slugger) Slug(input string) string {
+	slug, err := s.SlugE(input)
+	if err != nil {
+		return err.(*SlugError).Slug
+	}
+
+	return slug
+}
+
+// SlugE is like Slug but returns a *SlugError when Store holds every slug
+// tried.
+func (s *Slugger) SlugE(input string) (string, error) {
+	separator := s.Separator
+	if separator == "" {
+		separator = "-"
+	}
+	replacements := s.Replacements
+	if replacements == nil {
+		replacements = DefaultSlugReplacements
+	}
+
+	// Lowercasing comes first for the rules of the locale, and again after
+	// transliteration, which may produce uppercase letters.
+	text := toLower(replaceSymbols(input, replacements), s.Locale.Language())
+	if s.KeepUnicode {
+		text = NFCNormalizer(text)
+	} else {
+		text = strings.ToLower(transliterateToASCII(TransliterateNormalizer(s.Locale)(text), ""))
+	}
+
+	// Apostrophes join words: "don't" gives "dont".
+	text = strings.NewReplacer("'", "", "’", "").Replace(text)
+	words := strings.FieldsFunc(text, func(r rune) bool { return !isIdentifierRune(r) })
+	words = dropStopWords(words, s.StopWords)
+
+	slug := joinWithin(words, separator, s.MaxLength)
+	if s.Store == nil || slug == "" {
+		return slug, nil
+	}
+
+	attempts := s.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultSlugAttempts
+	}
+	candidate := slug
+	for n := 2; !s.Store.Claim(candidate); n++ {
+		if n > attempts {
+			return "", &SlugError{Slug: slug, Attempts: attempts}
+		}
+		suffix := separator + strconv.Itoa(n)
+		limit := s.MaxLength - utf8.RuneCountInString(suffix)
+		if s.MaxLength > 0 && limit < 1 {
+			limit = 1
+		}
+		candidate = joinWithin(words, separator, limit) + suffix
+		// A suffix as long as MaxLength leaves no room for the slug.
+		if s.MaxLength > 0 && utf8.RuneCountInString(candidate) > s.MaxLength {
+			candidate = truncateRunes(candidate, s.MaxLength)
+		}
+	}
+
+	return candidate, nil
+}
+
+// replaceSymbols surrounds the replacement of every symbol with spaces.
+// Symbols starting with a letter or a digit must start a word.
+func replaceSymbols(input string, replacements map[string]string) string {
+	if len(replacements) == 0 {
+		return input
+	}
+
+	symbols := make([]string, 0, len(replacements))
+	for symbol := range replacements {
+		if symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	// Longer symbols first, so "C#" wins over "#".
+	sort.Slice(symbols, func(i, j int) bool {
+		if len(symbols[i]) != len(symbols[j]) {
+			return len(symbols[i]) > len(symbols[j])
+		}
+		return symbols[i] < symbols[j]
+	})
+
+	var b strings.Builder
+	prev := ' '
+	for i := 0; i < len(input); {
+		matched := ""
+		for _, symbol := range symbols {
+			if !strings.HasPrefix(input[i:], symbol) {
+				continue
+			}
+			first, _ := utf8.DecodeRuneInString(symbol)
+			if isIdentifierRune(first) && isIdentifierRune(prev) {
+				continue
+			}
+			matched = symbol
+			break
+		}
+		if matched != "" {
+			b.WriteString(" " + replacements[matched] + " ")
+			i += len(matched)
+			prev = ' '
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(input[i:])
+		b.WriteString(input[i : i+size])
+		i += size
+		prev = r
+	}
+
+	return b.String()
+}
+
+func dropStopWords(words, stopWords []string) []string {
+	if len(stopWords) == 0 {
+		return words
+	}
+
+	stop := map[string]bool{}
+	for _, w := range stopWords {
+		stop[CaseFoldNormalizer(w)] = true
+	}
+	var kept []string
+	for _, w := range words {
+		if !stop[CaseFoldNormalizer(w)] {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == 0 {
+		return words
+	}
+
+	return kept
+}
+
+// joinWithin joins as many words as fit in maxLength characters. A first word
+// longer than maxLength is cut. A maxLength of zero or less means no limit.
+func joinWithin(words []string, separator string, maxLength int) string {
+	if maxLength <= 0 {
+		return strings.Join(words, separator)
+	}
+
+	var b strings.Builder
+	length := 0
+	for i, w := range words {
+		n := utf8.RuneCountInString(w)
+		if i > 0 {
+			n += utf8.RuneCountInString(separator)
+		}
+		if length+n > maxLength {
+			if i == 0 {
+				return truncateRunes(w, maxLength)
+			}
+			break
+		}
+		if i > 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(w)
+		length += n
+	}
+
+	return b.String()
+}
+
+// truncateRunes returns the first n runes of s, keeping combining marks with
+// the letter they follow.
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			if !unicode.IsMark(r) {
+				return s[:i]
+			}
+			continue
+		}
+		n--
+	}
+
+	return s
+}
@@ -0,0 +1,134 @@
+package textn8r
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSlugger(t *testing.T) {
+	tests := []struct {
+		slugger  Slugger
+		input    string
+		expected string
+	}{
+		{Slugger{}, "How to Create Amazing Web Apps in 2023!", "how-to-create-amazing-web-apps-in-2023"},
+		{Slugger{}, "C++ & C#", "c-plus-plus-and-c-sharp"},
+		{Slugger{}, "ABC# Corp", "abc-corp"},
+		{Slugger{}, "Don't Panic", "dont-panic"},
+		{Slugger{}, "  --Hello,   World--  ", "hello-world"},
+		{Slugger{}, "Crème Brûlée", "creme-brulee"},
+		{Slugger{}, "Москва 2024", "moskva-2024"},
+		{Slugger{}, "北京", "bei-jing"},
+		{Slugger{}, "Price: 5€", "price-5eur"},
+		{Slugger{Locale: LocaleGerman}, "Müller & Söhne", "mueller-and-soehne"},
+		{Slugger{Locale: LocaleTurkish}, "KIRMIZI", "kirmizi"},
+		{Slugger{Separator: "_"}, "Hello World", "hello_world"},
+		{Slugger{Replacements: map[string]string{}}, "Tom & Jerry", "tom-jerry"},
+		{Slugger{Replacements: map[string]string{"&": "y"}}, "Tom & Jerry", "tom-y-jerry"},
+		{Slugger{StopWords: []string{"a", "the", "of"}}, "The Lord of the Rings", "lord-rings"},
+		{Slugger{StopWords: []string{"the"}}, "The The", "the-the"},
+		{Slugger{MaxLength: 20}, "How to Create Amazing Web Apps", "how-to-create"},
+		{Slugger{MaxLength: 5}, "Supercalifragilistic", "super"},
+		{Slugger{KeepUnicode: true}, "Москва Сити", "москва-сити"},
+		{Slugger{KeepUnicode: true}, "Café Noir", "café-noir"},
+		{Slugger{}, "", ""},
+		{Slugger{}, "!!!", ""},
+	}
+
+	for _, tt := range tests {
+		result := tt.slugger.Slug(tt.input)
+		if result != tt.expected {
+			t.Errorf("%+v.Slug(%q) = %q; want %q", tt.slugger, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestSluggerUnique(t *testing.T) {
+	store := &MemorySlugStore{}
+	s := Slugger{Store: store}
+
+	for _, expected := range []string{"hello-world", "hello-world-2", "hello-world-3"} {
+		if result := s.Slug("Hello World"); result != expected {
+			t.Errorf("Slug(%q) = %q; want %q", "Hello World", result, expected)
+		}
+	}
+	// The suffix counts toward MaxLength.
+	short := Slugger{Store: store, MaxLength: 12}
+	for _, expected := range []string{"hello-there", "hello-2", "hello-3"} {
+		if result := short.Slug("Hello there"); result != expected {
+			t.Errorf("Slug(%q) = %q; want %q", "Hello there", result, expected)
+		}
+	}
+	// Even when the suffix leaves no room for the slug.
+	tiny := Slugger{Store: &MemorySlugStore{}, MaxLength: 3, MaxAttempts: 12}
+	for _, expected := range []string{"abc", "a-2", "a-3", "a-4", "a-5", "a-6", "a-7", "a-8", "a-9", "a-1"} {
+		if result, err := tiny.SlugE("abc"); err != nil || result != expected {
+			t.Errorf("SlugE(%q) = %q, %v; want %q", "abc", result, err, expected)
+		}
+	}
+	var slugErr *SlugError
+	if result, err := tiny.SlugE("abc"); !errors.As(err, &slugErr) {
+		t.Errorf("SlugE(%q) = %q, %v; want a *SlugError once every slug within MaxLength is taken", "abc", result, err)
+	}
+	if result := s.Slug("!!!"); result != "" {
+		t.Errorf("Slug(%q) = %q; want %q", "!!!", result, "")
+	}
+}
+
+func TestSluggerUniqueAttempts(t *testing.T) {
+	s := Slugger{Store: &MemorySlugStore{}, MaxAttempts: 3}
+
+	for _, expected := range []string{"post", "post-2", "post-3"} {
+		if result, err := s.SlugE("Post"); err != nil || result != expected {
+			t.Errorf("SlugE(%q) = %q, %v; want %q", "Post", result, err, expected)
+		}
+	}
+
+	_, err := s.SlugE("Post")
+	var slugErr *SlugError
+	if !errors.As(err, &slugErr) || slugErr.Slug != "post" || slugErr.Attempts != 3 {
+		t.Fatalf("SlugE(%q) error = %v; want a *SlugError for %q", "Post", err, "post")
+	}
+	if result := s.Slug("Post"); result != "post" {
+		t.Errorf("Slug(%q) = %q; want the fallback %q", "Post", result, "post")
+	}
+}
+
+func TestSluggerUniqueConcurrent(t *testing.T) {
+	s := Slugger{Store: &MemorySlugStore{}}
+
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slug := s.Slug("post")
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[slug] {
+				t.Errorf("Slug returned %q twice", slug)
+			}
+			seen[slug] = true
+		}()
+	}
+	wg.Wait()
+
+	for i := 2; i <= 50; i++ {
+		if slug := fmt.Sprintf("post-%d", i); !seen[slug] {
+			t.Errorf("missing slug %q", slug)
+		}
+	}
+}
+
+func TestSluggerNormalizer(t *testing.T) {
+	s := &Slugger{}
+	normalizers := Normalizers{TrimSpaceNormalizer, s.Normalizer()}
+
+	if result := normalizers.Apply("  Tom & Jerry  "); result != "tom-and-jerry" {
+		t.Errorf("Normalizers.Apply(%q) = %q; want %q", "  Tom & Jerry  ", result, "tom-and-jerry")
+	}
+}
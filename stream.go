@@ -0,0 +1,293 @@
+package textn8r
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scope tells how much of a stream a normalizer must see at once.
+type Scope int
+
+const (
+	// ScopeDocument normalizers need the whole input at once. It is the scope
+	// of custom normalizers unless they declare another one with WithScope.
+	ScopeDocument Scope = iota
+	// ScopeLine normalizers are applied to every line on its own, without its
+	// "\n" or "\r\n" line ending.
+	ScopeLine
+	// ScopeStream normalizers give the same result on the whole input and on
+	// its chunks, as long as chunks are split before a character that is not a
+	// combining mark and does not combine with the previous one.
+	ScopeStream
+)
+
+// String returns the name of the scope.
+func (s Scope) String() string {
+	switch s {
+	case ScopeDocument:
+		return "document"
+	case ScopeLine:
+		return "line"
+	case ScopeStream:
+		return "stream"
+	}
+
+	return "Scope(" + strconv.Itoa(int(s)) + ")"
+}
+
+// WithScope returns a Normalizer that runs n and declares the scope, so that
+// Reader and Writer know how to feed it. Scopes other than ScopeLine and
+// ScopeStream declare ScopeDocument.
+func WithScope(n Normalizer, scope Scope) Normalizer {
+	if scope != ScopeLine && scope != ScopeStream {
+		scope = ScopeDocument
+	}
+
+	d := &descriptor{normalize: n}
+	if described := describe(n); described != nil {
+		*d = *described
+	}
+	d.scope = scope
+
+	return d.normalizer()
+}
+
+// ScopeOf returns the scope of a normalizer: the one declared with WithScope,
+// the one of a built-in normalizer, or ScopeDocument.
+func ScopeOf(n Normalizer) Scope {
+	if d := describe(n); d != nil {
+		return d.scope
+	}
+
+	return ScopeDocument
+}
+
+// streamChunkSize is the size of the chunks Reader reads.
+const streamChunkSize = 32 * 1024
+
+// streamStage feeds one normalizer of a stream, holding back the input it
+// cannot normalize yet.
+type streamStage struct {
+	normalizer Normalizer
+	scope      Scope
+	// runs is the class of runes the normalizer replaces by runs, if any.
+	runs    func(rune) bool
+	pending []byte
+}
+
+func newStreamStages(normalizers Normalizers) []*streamStage {
+	stages := make([]*streamStage, 0, len(normalizers))
+	for _, n := range normalizers {
+		stage := &streamStage{normalizer: n, scope: ScopeDocument}
+		if d := describe(n); d != nil {
+			stage.scope, stage.runs = d.scope, d.runs
+		}
+		stages = append(stages, stage)
+	}
+
+	return stages
+}
+
+// process returns the normalized output for the data that can be normalized
+// so far. At the end of the stream, final is set and nothing is held back.
+func (s *streamStage) process(data []byte, final bool) []byte {
+	s.pending = append(s.pending, data...)
+
+	cut := len(s.pending)
+	if !final {
+		switch s.scope {
+		case ScopeDocument:
+			return nil
+		case ScopeLine:
+			cut = bytes.LastIndexByte(s.pending, '\n') + 1
+		case ScopeStream:
+			cut = streamCut(s.pending, s.runs)
+		}
+	}
+	if cut == 0 {
+		return nil
+	}
+
+	var out []byte
+	if s.scope == ScopeLine {
+		out = s.processLines(s.pending[:cut])
+	} else {
+		out = []byte(s.normalizer(string(s.pending[:cut])))
+	}
+	s.pending = append(s.pending[:0], s.pending[cut:]...)
+
+	return out
+}
+
+// processLines normalizes every line on its own, keeping line endings.
+func (s *streamStage) processLines(data []byte) []byte {
+	var out []byte
+	for len(data) > 0 {
+		line, ending := data, []byte(nil)
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, ending = data[:i], data[i:i+1]
+			if i > 0 && data[i-1] == '\r' {
+				line, ending = data[:i-1], data[i-1:i+1]
+			}
+		}
+		out = append(out, s.normalizer(string(line))...)
+		out = append(out, ending...)
+		data = data[len(line)+len(ending):]
+	}
+
+	return out
+}
+
+// streamCut returns the last position where data can be split for a
+// ScopeStream normalizer, or 0. The rune after the split must be a stable
+// starter, and the rune before it must not be in runs. A rune that is not
+// complete yet at the end of data is never split from what precedes it.
+func streamCut(data []byte, runs func(rune) bool) int {
+	end := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				end = i
+			}
+			break
+		}
+	}
+
+	for i := end; i > 0; {
+		prev, size := utf8.DecodeLastRune(data[:i])
+		if i < end {
+			next, _ := utf8.DecodeRune(data[i:])
+			if isStableStarter(next) && (runs == nil || !runs(prev)) {
+				return i
+			}
+		}
+		i -= size
+	}
+
+	return 0
+}
+
+// isStableStarter reports whether no normalization form nor accent folding
+// combines r, or the start of its decomposition, with what precedes it.
+func isStableStarter(r rune) bool {
+	if r < 0x300 {
+		return true
+	}
+	if unicode.IsMark(r) || combiningClass(r) != 0 || combinesBackward(r) {
+		return false
+	}
+
+	d, ok := compatibilityDecompositionTable[r]
+	if !ok {
+		d, ok = canonicalDecompositionTable[r]
+	}
+	if ok {
+		first, _ := utf8.DecodeRuneInString(d)
+		return first == r || isStableStarter(first)
+	}
+
+	return true
+}
+
+// Reader normalizes the text read from an underlying reader.
+type Reader struct {
+	r      io.Reader
+	stages []*streamStage
+	buf    []byte
+	out    []byte
+	err    error
+}
+
+// NewReader returns a Reader that applies the normalizers to the text read
+// from r. The text is read in chunks and each normalizer is fed according to
+// its scope: ScopeStream normalizers see chunks split between characters,
+// ScopeLine normalizers see one line at a time and ScopeDocument normalizers
+// see the whole text once r is exhausted.
+func NewReader(r io.Reader, normalizers Normalizers) *Reader {
+	return &Reader{
+		r:      r,
+		stages: newStreamStages(normalizers),
+		buf:    make([]byte, streamChunkSize),
+	}
+}
+
+// Read reads normalized text into p.
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 && r.err == nil {
+		n, err := r.r.Read(r.buf)
+		// The bytes read come before the error, which is returned once the
+		// output is consumed.
+		r.out = runStages(r.stages, r.buf[:n], err == io.EOF)
+		r.err = err
+	}
+
+	if len(r.out) > 0 {
+		n := copy(p, r.out)
+		r.out = r.out[n:]
+		return n, nil
+	}
+
+	return 0, r.err
+}
+
+// Writer normalizes the text written to it before writing it to an
+// underlying writer. Text held back by normalizers that need more context is
+// written by Close.
+type Writer struct {
+	w      io.Writer
+	stages []*streamStage
+	closed bool
+}
+
+// NewWriter returns a Writer that applies the normalizers to the text written
+// to it and writes the result to w. Normalizers are fed according to their
+// scope, as with NewReader.
+func NewWriter(w io.Writer, normalizers Normalizers) *Writer {
+	return &Writer{
+		w:      w,
+		stages: newStreamStages(normalizers),
+	}
+}
+
+// Write normalizes p as far as possible and writes the result.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if out := runStages(w.stages, p, false); len(out) > 0 {
+		if _, err := w.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close normalizes and writes the text held back so far. It does not close
+// the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if out := runStages(w.stages, nil, true); len(out) > 0 {
+		if _, err := w.w.Write(out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runStages(stages []*streamStage, data []byte, final bool) []byte {
+	for _, s := range stages {
+		data = s.process(data, final)
+	}
+
+	return data
+}
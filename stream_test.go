@@ -0,0 +1,181 @@
+package textn8r
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// streamText mixes scripts, combining marks, runs of symbols and line endings
+// so that chunk boundaries fall everywhere.
+var streamText = strings.Repeat("Crème Brûlée\tà la carte -- 42%!\r\n"+
+	"Café ΟΔΥΣΣΕΥΣ 한국어 각 ﬁ ｶﾞ\n"+
+	"Ærøskøbing ŁÓDŹ @@@ ~ñ \xff\xe2\x82 end\n", 50)
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name        string
+		normalizers Normalizers
+	}{
+		{"stream", Normalizers{LowerCaseNormalizer, ReplaceAccentsNormalizer, ReplaceTabNormalizer(" ")}},
+		{"forms", Normalizers{NFDNormalizer, NFCNormalizer, NFKCNormalizer}},
+		{"runs", Normalizers{ReplaceNonAlphanumericNormalizer("-"), RemoveSpecialCharactersNormalizer}},
+		{"diacritics", Normalizers{ReplaceDiacriticsNormalizer("?"), RemovePunctuationNormalizer}},
+		{"document", Normalizers{RemoveExtraSpaceNormalizer, UpperCaseNormalizer}},
+		{"line", Normalizers{LowerCaseLocaleNormalizer(LocaleDefault), TransliterateToASCIINormalizer("?")}},
+		{"empty", Normalizers{}},
+	}
+
+	readers := map[string]func(io.Reader) io.Reader{
+		"whole":    func(r io.Reader) io.Reader { return r },
+		"one byte": iotest.OneByteReader,
+		"half":     iotest.HalfReader,
+		"data err": iotest.DataErrReader,
+	}
+
+	for _, tt := range tests {
+		expected := tt.normalizers.Apply(streamText)
+		for name, wrap := range readers {
+			result, err := io.ReadAll(NewReader(wrap(strings.NewReader(streamText)), tt.normalizers))
+			if err != nil {
+				t.Errorf("%s/%s: ReadAll() error = %v", tt.name, name, err)
+				continue
+			}
+			if string(result) != expected {
+				t.Errorf("%s/%s: NewReader() = %+q; want %+q", tt.name, name, result, expected)
+			}
+		}
+	}
+}
+
+func TestReaderError(t *testing.T) {
+	r := NewReader(iotest.TimeoutReader(strings.NewReader("hello world")), Normalizers{UpperCaseNormalizer})
+
+	_, err := io.ReadAll(r)
+	if err != iotest.ErrTimeout {
+		t.Errorf("ReadAll() error = %v; want %v", err, iotest.ErrTimeout)
+	}
+}
+
+// dataErrReader returns its data along with a non-EOF error.
+type dataErrReader struct {
+	data string
+	err  error
+}
+
+func (r *dataErrReader) Read(p []byte) (int, error) {
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, r.err
+}
+
+func TestReaderDataWithError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := NewReader(&dataErrReader{data: "hello world\n", err: errRead}, Normalizers{UpperCaseNormalizer})
+
+	result, err := io.ReadAll(r)
+	if err != errRead {
+		t.Errorf("ReadAll() error = %v; want %v", err, errRead)
+	}
+	// The line feed is held back until the next rune shows it can be split.
+	if string(result) != "HELLO WORLD" {
+		t.Errorf("ReadAll() = %q; want %q", result, "HELLO WORLD")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	normalizers := Normalizers{ReplaceAccentsNormalizer, ReplaceNonAlphanumericNormalizer("-"), LowerCaseNormalizer}
+	expected := normalizers.Apply(streamText)
+
+	for _, size := range []int{1, 2, 3, 7, 64, 4096} {
+		var b bytes.Buffer
+		w := NewWriter(&b, normalizers)
+		for i := 0; i < len(streamText); i += size {
+			if _, err := w.Write([]byte(streamText[i:min(i+size, len(streamText))])); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if b.String() != expected {
+			t.Errorf("NewWriter() with writes of %d bytes = %+q; want %+q", size, b.String(), expected)
+		}
+	}
+}
+
+func TestWriterHoldsBackDocument(t *testing.T) {
+	var b bytes.Buffer
+	w := NewWriter(&b, Normalizers{TrimSpaceNormalizer})
+
+	if _, err := w.Write([]byte("  hello  ")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("Write() wrote %q before Close; want nothing", b.String())
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if b.String() != "hello" {
+		t.Errorf("Close() wrote %q; want %q", b.String(), "hello")
+	}
+	if _, err := w.Write([]byte("more")); err == nil {
+		t.Error("Write() after Close succeeded; want an error")
+	}
+}
+
+func TestLineScope(t *testing.T) {
+	trim := WithScope(TrimSpaceNormalizer, ScopeLine)
+	input := "  one  \r\n\ttwo\t\n three"
+
+	result, err := io.ReadAll(NewReader(iotest.OneByteReader(strings.NewReader(input)), Normalizers{trim}))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(result) != "one\r\ntwo\nthree" {
+		t.Errorf("NewReader() = %q; want %q", result, "one\r\ntwo\nthree")
+	}
+}
+
+func TestScopeOf(t *testing.T) {
+	tests := []struct {
+		normalizer Normalizer
+		expected   Scope
+	}{
+		{UpperCaseNormalizer, ScopeStream},
+		{ReplaceAccentsNormalizer, ScopeStream},
+		{ReplaceTabNormalizer("-"), ScopeStream},
+		{ReplaceNonAlphanumericNormalizer("-"), ScopeStream},
+		{NFCNormalizer, ScopeStream},
+		{LowerCaseLocaleNormalizer(LocaleTurkish), ScopeLine},
+		{TransliterateToASCIINormalizer("?"), ScopeLine},
+		{TrimSpaceNormalizer, ScopeDocument},
+		{RemoveExtraSpaceNormalizer, ScopeDocument},
+		{func(input string) string { return input }, ScopeDocument},
+		{WithScope(TrimSpaceNormalizer, ScopeLine), ScopeLine},
+		{WithScope(strings.ToUpper, ScopeStream), ScopeStream},
+		{WithScope(UpperCaseNormalizer, ScopeDocument), ScopeDocument},
+		{WithScope(UpperCaseNormalizer, Scope(42)), ScopeDocument},
+		{WithScope(WithScope(TrimSpaceNormalizer, ScopeLine), ScopeStream), ScopeStream},
+	}
+
+	for i, tt := range tests {
+		if result := ScopeOf(tt.normalizer); result != tt.expected {
+			t.Errorf("ScopeOf(tests[%d]) = %v; want %v", i, result, tt.expected)
+		}
+	}
+}
+
+func BenchmarkReader(b *testing.B) {
+	normalizers := Normalizers{LowerCaseNormalizer, ReplaceAccentsNormalizer, ReplaceTabNormalizer(" ")}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(streamText)))
+	for i := 0; i < b.N; i++ {
+		_, _ = io.Copy(io.Discard, NewReader(strings.NewReader(streamText), normalizers))
+	}
+}
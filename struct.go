@@ -0,0 +1,428 @@
+package textn8r
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Struct normalizes in place the strings of the value v points to, following
+// the normalize tags of its struct fields. A tag holds a pipeline in the
+// pipeline syntax, whose steps can also be separated by commas:
+//
+//	type User struct {
+//		Name    string            `normalize:"trim,collapse(\" \")"`
+//		Email   *string           `normalize:"trim | lower"`
+//		Tags    []string          `normalize:"slug"`
+//		Labels  map[string]string `normalize:"trim"`
+//		Address *Address
+//	}
+//
+// Tagged fields can be of any type whose kind is string, or pointers, slices,
+// arrays and maps of them; the pipeline applies to every string they hold,
+// the values of maps but not their keys. Struct walks into untagged fields
+// holding structs, through pointers, slices, arrays and maps, and skips
+// fields tagged "-", unexported fields and interfaces. Every pointer is
+// followed once, so values with pointer cycles, such as trees with parent
+// pointers, are walked once each.
+//
+// Tags can name pipelines registered with RegisterPipeline. The tags of each
+// type are parsed once and the resulting plan is reused. Struct stops at the
+// first rejected string, leaving it unchanged, and returns a *FieldError.
+// Invalid tags give a *TagError.
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		name := "nil"
+		if v != nil {
+			name = rv.Type().String()
+		}
+		return &ArgumentError{Normalizer: "struct", Reason: "want a non-nil pointer, got " + name}
+	}
+
+	p, err := planOf(rv.Type().Elem())
+	if err != nil {
+		return err
+	}
+	if p.walk == nil {
+		return nil
+	}
+
+	s := walkStates.Get().(*walkState)
+	defer func() {
+		clear(s.visited)
+		walkStates.Put(s)
+	}()
+	s.visit(rv)
+	if err := p.walk(s, rv.Elem()); err != nil {
+		fieldErr := err.(*FieldError)
+		fieldErr.Path = strings.TrimPrefix(fieldErr.Path, ".")
+		return fieldErr
+	}
+
+	return nil
+}
+
+// FieldError reports a string of a struct rejected by the pipeline of its
+// tag.
+type FieldError struct {
+	// Path locates the string from the value given to Struct, such as
+	// Users[2].Email or Labels["team"].
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return "textn8r: field " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error of the pipeline.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// TagError reports an invalid normalize tag.
+type TagError struct {
+	// Type is the struct type holding the field.
+	Type  string
+	Field string
+	Tag   string
+	Err   error
+}
+
+func (e *TagError) Error() string {
+	return "textn8r: tag of " + e.Type + "." + e.Field + " " + strconv.Quote(e.Tag) + ": " + strings.TrimPrefix(e.Err.Error(), "textn8r: ")
+}
+
+// Unwrap returns the error of the tag.
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+// walker normalizes the strings of an addressable value. It returns
+// *FieldError values, whose paths every level prefixes.
+type walker func(s *walkState, v reflect.Value) error
+
+// walkState is the state of a call to Struct.
+type walkState struct {
+	// visited holds the pointers followed so far. A pointer to a struct and
+	// a pointer to its first field share their address but not their type.
+	visited map[pointerVisit]bool
+}
+
+type pointerVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// visit records the pointer and reports whether it was not followed before.
+func (s *walkState) visit(v reflect.Value) bool {
+	key := pointerVisit{ptr: v.Pointer(), typ: v.Type()}
+	if s.visited[key] {
+		return false
+	}
+	if s.visited == nil {
+		s.visited = map[pointerVisit]bool{}
+	}
+	s.visited[key] = true
+
+	return true
+}
+
+// walkStates holds the states of Struct, whose visited maps are reused.
+var walkStates = sync.Pool{
+	New: func() any { return new(walkState) },
+}
+
+// typePlan holds the walker of a type, which is nil when the type holds no
+// tagged strings. The plans of recursive types refer to themselves, so
+// walkers read the walk field of the plans they use when they run.
+type typePlan struct {
+	walk walker
+	done bool
+}
+
+// empty reports whether the plan is known to have nothing to walk.
+func (p *typePlan) empty() bool {
+	return p.done && p.walk == nil
+}
+
+// structPlan is the cached plan of a type, or the error of its tags.
+type structPlan struct {
+	plan *typePlan
+	err  error
+	// generation is the registry generation the plan was built from. Tags
+	// name registered normalizers, so Register makes the plan stale.
+	generation uint64
+}
+
+// structPlans holds the structPlan of the types given to Struct.
+var structPlans sync.Map
+
+func planOf(t reflect.Type) (*typePlan, error) {
+	// The generation is read first, so a plan built while Register runs is
+	// stored as stale.
+	generation := registryGeneration.Load()
+	if cached, ok := structPlans.Load(t); ok {
+		if p := cached.(structPlan); p.generation == generation {
+			return p.plan, p.err
+		}
+	}
+
+	b := planBuilder{plans: map[reflect.Type]*typePlan{}}
+	p, err := b.plan(t)
+	structPlans.Store(t, structPlan{plan: p, err: err, generation: generation})
+
+	return p, err
+}
+
+// planBuilder builds the plans of a type and of the types it holds.
+type planBuilder struct {
+	plans map[reflect.Type]*typePlan
+}
+
+// plan returns the plan of untagged values of the type.
+func (b *planBuilder) plan(t reflect.Type) (*typePlan, error) {
+	if p, ok := b.plans[t]; ok {
+		return p, nil
+	}
+	p := &typePlan{}
+	b.plans[t] = p
+
+	switch t.Kind() {
+	case reflect.Struct:
+		walk, err := b.structWalker(t)
+		if err != nil {
+			return nil, err
+		}
+		p.walk = walk
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		elem, err := b.plan(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		p.walk = containerWalker(t, elem)
+	}
+	p.done = true
+
+	return p, nil
+}
+
+// structWalker returns the walker of the fields of a struct type.
+func (b *planBuilder) structWalker(t reflect.Type) (walker, error) {
+	type field struct {
+		index int
+		// path is empty for embedded structs, whose fields are promoted.
+		path string
+		plan *typePlan
+	}
+
+	var fields []field
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("normalize")
+		if tag == "-" {
+			continue
+		}
+
+		tagError := func(err error) error {
+			return &TagError{Type: t.String(), Field: f.Name, Tag: tag, Err: err}
+		}
+		embedded := f.Anonymous && f.Type.Kind() == reflect.Struct
+		if !f.IsExported() && (tagged || !embedded) {
+			// Exported fields of unexported embedded structs can be set.
+			if tagged {
+				return nil, tagError(errors.New("field is not exported"))
+			}
+			continue
+		}
+
+		var p *typePlan
+		var err error
+		if tagged {
+			var n NormalizerE
+			if n, err = parseStructTag(tag); err != nil {
+				return nil, tagError(err)
+			}
+			if p, err = taggedPlan(f.Type, n, nil); err != nil {
+				return nil, tagError(err)
+			}
+		} else if p, err = b.plan(f.Type); err != nil {
+			return nil, err
+		}
+		if p.empty() {
+			continue
+		}
+
+		path := "." + f.Name
+		if embedded && !tagged {
+			path = ""
+		}
+		fields = append(fields, field{index: i, path: path, plan: p})
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return func(s *walkState, v reflect.Value) error {
+		for _, f := range fields {
+			if f.plan.walk == nil {
+				continue
+			}
+			if err := f.plan.walk(s, v.Field(f.index)); err != nil {
+				return prefixPath(f.path, err)
+			}
+		}
+		return nil
+	}, nil
+}
+
+// taggedPlan returns the plan applying the normalizer to every string of a
+// tagged field of the type. seen holds the types being planned, to reject
+// recursive ones.
+func taggedPlan(t reflect.Type, n NormalizerE, seen []reflect.Type) (*typePlan, error) {
+	for _, s := range seen {
+		if s == t {
+			return nil, errors.New("recursive type " + t.String())
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &typePlan{walk: stringWalker(n), done: true}, nil
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		elem, err := taggedPlan(t.Elem(), n, append(seen, t))
+		if err != nil {
+			return nil, err
+		}
+		return &typePlan{walk: containerWalker(t, elem), done: true}, nil
+	}
+
+	return nil, errors.New("tags apply to strings, not " + t.String())
+}
+
+// parseStructTag builds the pipeline of a tag. Commas outside of arguments
+// separate steps like "|".
+func parseStructTag(tag string) (NormalizerE, error) {
+	src := []byte(tag)
+	depth, quoted := 0, false
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			src[i] = '|'
+		}
+	}
+
+	if spec, err := ParseSpec(string(src)); err == nil {
+		if n, err := spec.Compile(); err == nil {
+			return n.NormalizerE(), nil
+		}
+	}
+	p, err := ParsePipeline(string(src))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.NormalizerE(), nil
+}
+
+func stringWalker(n NormalizerE) walker {
+	return func(_ *walkState, v reflect.Value) error {
+		s := v.String()
+		output, err := n(s)
+		if err != nil {
+			return &FieldError{Err: err}
+		}
+		if output != s {
+			v.SetString(output)
+		}
+		return nil
+	}
+}
+
+// containerWalker returns the walker of a pointer, slice, array or map type
+// whose elements have the plan.
+func containerWalker(t reflect.Type, elem *typePlan) walker {
+	if elem.empty() {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return func(s *walkState, v reflect.Value) error {
+			if v.IsNil() || elem.walk == nil || !s.visit(v) {
+				return nil
+			}
+			return elem.walk(s, v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		return func(s *walkState, v reflect.Value) error {
+			if elem.walk == nil {
+				return nil
+			}
+			for i := range v.Len() {
+				if err := elem.walk(s, v.Index(i)); err != nil {
+					return prefixPath("["+strconv.Itoa(i)+"]", err)
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		return func(s *walkState, v reflect.Value) error {
+			if v.Len() == 0 || elem.walk == nil {
+				return nil
+			}
+			// Map values cannot be set in place, so each one is copied,
+			// normalized and stored back.
+			value := reflect.New(t.Elem()).Elem()
+			iter := v.MapRange()
+			for iter.Next() {
+				value.SetIterValue(iter)
+				if err := elem.walk(s, value); err != nil {
+					return prefixPath("["+formatMapKey(iter.Key())+"]", err)
+				}
+				v.SetMapIndex(iter.Key(), value)
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// formatMapKey returns the key of a map as it appears in the paths of
+// FieldError.
+func formatMapKey(key reflect.Value) string {
+	switch key.Kind() {
+	case reflect.String:
+		return strconv.Quote(key.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(key.Bool())
+	}
+
+	return key.Type().String()
+}
+
+// prefixPath adds the segment to the front of the path of a *FieldError.
+func prefixPath(segment string, err error) error {
+	if fieldErr, ok := err.(*FieldError); ok {
+		fieldErr.Path = segment + fieldErr.Path
+	}
+
+	return err
+}
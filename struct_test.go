@@ -0,0 +1,254 @@
+package textn8r
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type structAddress struct {
+	Street string `normalize:"trim,title_case"`
+	City   string
+}
+
+type structLabel string
+
+type structBase struct {
+	ID string `normalize:"upper"`
+}
+
+type structUser struct {
+	structBase
+	Name      string                    `normalize:"trim,collapse(\" \")"`
+	Email     *string                   `normalize:"trim | lower"`
+	Handle    structLabel               `normalize:"trim,lower"`
+	Tags      []string                  `normalize:"slug"`
+	Labels    map[string]string         `normalize:"upper"`
+	Codes     [2]*string                `normalize:"trim"`
+	Raw       string                    `normalize:"-"`
+	Plain     string                    // no tag
+	Address   *structAddress            // walked
+	Addresses map[string]*structAddress // walked
+	Friends   []structUser
+	Note      any `normalize:"-"`
+	secret    string
+}
+
+type structNode struct {
+	Name     string `normalize:"upper"`
+	Parent   *structNode
+	Children []*structNode
+}
+
+func TestStruct(t *testing.T) {
+	email, code := "  Jane@Example.COM ", " x "
+	u := structUser{
+		structBase: structBase{ID: "ab1"},
+		Name:       "  Jane   Doe ",
+		Email:      &email,
+		Handle:     " JaneD ",
+		Tags:       []string{"Go Lang", "Crème Brûlée"},
+		Labels:     map[string]string{"team": "core"},
+		Codes:      [2]*string{&code, nil},
+		Raw:        " raw ",
+		Plain:      " plain ",
+		Address:    &structAddress{Street: " main street ", City: " city "},
+		Addresses:  map[string]*structAddress{"home": {Street: "elm road"}, "none": nil},
+		Friends:    []structUser{{Name: " Bob "}},
+		secret:     " secret ",
+	}
+	if err := Struct(&u); err != nil {
+		t.Fatal(err)
+	}
+
+	email, code = "jane@example.com", "x"
+	expected := structUser{
+		structBase: structBase{ID: "AB1"},
+		Name:       "Jane Doe",
+		Email:      &email,
+		Handle:     "janed",
+		Tags:       []string{"go-lang", "creme-brulee"},
+		Labels:     map[string]string{"team": "CORE"},
+		Codes:      [2]*string{&code, nil},
+		Raw:        " raw ",
+		Plain:      " plain ",
+		Address:    &structAddress{Street: "Main Street", City: " city "},
+		Addresses:  map[string]*structAddress{"home": {Street: "Elm Road"}, "none": nil},
+		Friends:    []structUser{{Name: "Bob"}},
+		secret:     " secret ",
+	}
+	if !reflect.DeepEqual(u, expected) {
+		t.Errorf("Struct() = %+v; want %+v", u, expected)
+	}
+
+	tree := &structNode{Name: "a", Children: []*structNode{{Name: "b", Children: []*structNode{{Name: "c"}}}}}
+	if err := Struct(&tree); err != nil {
+		t.Fatal(err)
+	}
+	if c := tree.Children[0].Children[0]; tree.Name != "A" || c.Name != "C" {
+		t.Errorf("Struct() of a tree = %q, %q; want A, C", tree.Name, c.Name)
+	}
+
+	users := []structUser{{Tags: []string{"A B"}}}
+	if err := Struct(&users); err != nil || users[0].Tags[0] != "a-b" {
+		t.Errorf("Struct() of a slice = %v, %v; want a-b", users[0].Tags, err)
+	}
+}
+
+func TestStructCycles(t *testing.T) {
+	root := &structNode{Name: "root"}
+	child := &structNode{Name: "child", Parent: root}
+	root.Children = []*structNode{child, child}
+	child.Children = []*structNode{root}
+	root.Parent = root
+
+	if err := Struct(root); err != nil {
+		t.Fatal(err)
+	}
+	if root.Name != "ROOT" || child.Name != "CHILD" {
+		t.Errorf("Struct() of a cyclic tree = %q, %q; want ROOT, CHILD", root.Name, child.Name)
+	}
+	// The visited pointers are forgotten between calls.
+	child.Name = "again"
+	if err := Struct(&root); err != nil || child.Name != "AGAIN" {
+		t.Errorf("second Struct() = %q, %v; want AGAIN", child.Name, err)
+	}
+}
+
+func TestStructPipelines(t *testing.T) {
+	type contact struct {
+		Email string `normalize:"test_email"`
+	}
+
+	if err := RegisterPipeline("test_email", "trim | lower"); err != nil {
+		t.Fatal(err)
+	}
+	c := contact{Email: " A@X "}
+	if err := Struct(&c); err != nil || c.Email != "a@x" {
+		t.Errorf("Struct() = %q, %v; want a@x", c.Email, err)
+	}
+
+	// Registering again replaces the cached plans.
+	if err := RegisterPipeline("test_email", "trim | upper"); err != nil {
+		t.Fatal(err)
+	}
+	c = contact{Email: " a@x "}
+	if err := Struct(&c); err != nil || c.Email != "A@X" {
+		t.Errorf("Struct() = %q, %v; want A@X", c.Email, err)
+	}
+
+	d, ok := Lookup("test_email")
+	if !ok || d.Category != CategoryPipeline || d.Description != "trim | upper" || d.Fallible() {
+		t.Errorf("Lookup() = %+v, %v", d, ok)
+	}
+
+	if err := RegisterPipeline("test_require", "trim | test_require_at_struct"); err == nil {
+		t.Error("RegisterPipeline() with an unknown step succeeded")
+	}
+}
+
+func TestStructPipelinesRegisteredConcurrently(t *testing.T) {
+	type contact struct {
+		Email string `normalize:"test_concurrent"`
+	}
+
+	if err := RegisterPipeline("test_concurrent", "lower"); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				c := contact{Email: "a"}
+				_ = Struct(&c)
+			}
+		}()
+	}
+	for _, src := range []string{"upper", "lower", "upper"} {
+		if err := RegisterPipeline("test_concurrent", src); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	// No plan built before the last Register survives it.
+	c := contact{Email: "a"}
+	if err := Struct(&c); err != nil || c.Email != "A" {
+		t.Errorf("Struct() = %q, %v; want A", c.Email, err)
+	}
+}
+
+func TestStructErrors(t *testing.T) {
+	if err := Register(Definition{
+		Name:     "test_require_at_struct",
+		Category: CategoryValidation,
+		NewE:     func(Args) (NormalizerE, error) { return requireAt, nil },
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	type inner struct {
+		Email string `normalize:"trim,test_require_at_struct"`
+	}
+	type outer struct {
+		Users map[string][]inner
+	}
+
+	o := outer{Users: map[string][]inner{"a": {{Email: " @ "}, {Email: " b "}}}}
+	err := Struct(&o)
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Path != `Users["a"][1].Email` {
+		t.Fatalf("Struct() error = %v; want a *FieldError at Users[\"a\"][1].Email", err)
+	}
+	if expected := `textn8r: field Users["a"][1].Email: textn8r: step "test_require_at_struct": invalid input "b": missing "@"`; err.Error() != expected {
+		t.Errorf("Struct() error = %v; want %s", err, expected)
+	}
+
+	type unknown struct {
+		A string `normalize:"trim,lowr"`
+	}
+	type badType struct {
+		A int `normalize:"trim"`
+	}
+	type unexported struct {
+		a string `normalize:"trim"`
+	}
+	tests := []struct {
+		name     string
+		v        any
+		expected string
+	}{
+		{"unknown", &unknown{}, `textn8r: tag of textn8r.unknown.A "trim,lowr": line 1, column 6: unknown normalizer "lowr"`},
+		{"type", &badType{}, `textn8r: tag of textn8r.badType.A "trim": tags apply to strings, not int`},
+		{"unexported", &unexported{}, `textn8r: tag of textn8r.unexported.a "trim": field is not exported`},
+		{"not a pointer", unknown{}, `textn8r: normalizer "struct": want a non-nil pointer, got textn8r.unknown`},
+		{"nil", nil, `textn8r: normalizer "struct": want a non-nil pointer, got nil`},
+	}
+	for _, tt := range tests {
+		if err := Struct(tt.v); err == nil || err.Error() != tt.expected {
+			t.Errorf("%s: Struct() error = %v; want %s", tt.name, err, tt.expected)
+		}
+	}
+}
+
+func TestStructAllocs(t *testing.T) {
+	type user struct {
+		Name  string   `normalize:"trim,lower"`
+		Tags  []string `normalize:"trim"`
+		Plain string
+	}
+	u := &user{Name: "jane", Tags: []string{"a", "b"}}
+	if err := Struct(u); err != nil {
+		t.Fatal(err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = Struct(u)
+	})
+	if allocs > 0 {
+		t.Errorf("Struct() of a normalized value allocates %v times; want 0", allocs)
+	}
+}
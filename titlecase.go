@@ -0,0 +1,283 @@
+package textn8r
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TitleCaseStyle selects the style guide followed by TitleCaseNormalizer.
+type TitleCaseStyle int
+
+const (
+	// TitleCaseAP follows the Associated Press Stylebook: articles, and
+	// conjunctions and prepositions of up to three letters are lowercase.
+	TitleCaseAP TitleCaseStyle = iota
+	// TitleCaseChicago follows the Chicago Manual of Style: articles,
+	// coordinating conjunctions and all prepositions are lowercase.
+	TitleCaseChicago
+	// TitleCaseAPA follows the APA Publication Manual: words of up to three
+	// letters that are articles, conjunctions or prepositions are lowercase.
+	TitleCaseAPA
+	// TitleCaseSpanish follows the Spanish rule: only the first word and
+	// proper nouns are capitalized.
+	TitleCaseSpanish
+)
+
+// String returns the name of the style.
+func (s TitleCaseStyle) String() string {
+	switch s {
+	case TitleCaseAP:
+		return "AP"
+	case TitleCaseChicago:
+		return "Chicago"
+	case TitleCaseAPA:
+		return "APA"
+	case TitleCaseSpanish:
+		return "Spanish"
+	}
+
+	return "TitleCaseStyle(" + strconv.Itoa(int(s)) + ")"
+}
+
+var (
+	apMinorWords = wordSet("a an the and but for nor or so yet as at by en if in of off on out per to up via vs")
+
+	chicagoMinorWords = wordSet("a an the and but for nor or as " +
+		"aboard about above across after against along amid among around at atop before behind below beneath " +
+		"beside besides between beyond but by concerning despite down during except for from in inside into " +
+		"like near of off on onto opposite out outside over past per plus regarding round save since than " +
+		"through throughout till to toward towards under underneath unlike until up upon versus via vs with " +
+		"within without")
+
+	apaMinorWords = wordSet("a an the and as but for if nor or so yet at by in of off on per to up via vs")
+)
+
+func wordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+
+	return set
+}
+
+// TitleCaseNormalizer converts the input string to title case following the
+// style guide. The first and last words, and the first word after a colon or
+// a dash, are always capitalized, except in the Spanish style which only
+// capitalizes the first word. Each part of a hyphenated compound is treated as
+// a word, and only the first letter of a word is changed, so "don't" becomes
+// "Don't".
+//
+// Unless the whole input is uppercase, words written in uppercase such as
+// "NASA" or with inner capitals such as "iPhone" are kept as they are, and in
+// the Spanish style so are capitalized words, which are taken for proper
+// nouns. Words listed in exceptions are always written exactly as given.
+func TitleCaseNormalizer(style TitleCaseStyle, exceptions ...string) Normalizer {
+	minor := map[string]bool{}
+	switch style {
+	case TitleCaseAP:
+		minor = apMinorWords
+	case TitleCaseChicago:
+		minor = chicagoMinorWords
+	case TitleCaseAPA:
+		minor = apaMinorWords
+	}
+	exact := exceptionSet(exceptions)
+
+	return func(input string) string {
+		c := newCaser(input, exact)
+		words := c.words()
+		for i, w := range words {
+			if e, ok := c.exact[CaseFoldNormalizer(w.core)]; ok {
+				c.set(w, e)
+				continue
+			}
+
+			parts := strings.Split(w.core, "-")
+			for j, part := range parts {
+				first := j == 0 && (i == 0 || w.afterBreak)
+				last := i == len(words)-1 && j == len(parts)-1
+				if s, ok := c.fixed(part, style == TitleCaseSpanish && !first); ok {
+					parts[j] = s
+					continue
+				}
+
+				switch {
+				case style == TitleCaseSpanish && !first:
+					parts[j] = toLower(part, LocaleDefault)
+				case style != TitleCaseSpanish && !first && !last && minor[CaseFoldNormalizer(part)]:
+					parts[j] = toLower(part, LocaleDefault)
+				default:
+					parts[j] = capitalize(part)
+				}
+			}
+			c.set(w, strings.Join(parts, "-"))
+		}
+
+		return c.String()
+	}
+}
+
+// SentenceCaseNormalizer converts the input string to sentence case: the first
+// word of every sentence is capitalized and every other word is lowercase.
+// This fixes all-uppercase input such as "BREAKING NEWS. MORE SOON". Unless the
+// whole input is uppercase, words written in uppercase or with inner capitals
+// are kept as they are. Words listed in exceptions, such as proper nouns or
+// the English pronoun "I", are always written exactly as given.
+func SentenceCaseNormalizer(exceptions ...string) Normalizer {
+	exact := exceptionSet(exceptions)
+
+	return func(input string) string {
+		c := newCaser(input, exact)
+		for _, w := range c.words() {
+			switch s, ok := c.fixed(w.core, false); {
+			case ok:
+				c.set(w, s)
+			case w.sentenceStart:
+				c.set(w, capitalize(w.core))
+			default:
+				c.set(w, toLower(w.core, LocaleDefault))
+			}
+		}
+
+		return c.String()
+	}
+}
+
+func exceptionSet(exceptions []string) map[string]string {
+	exact := map[string]string{}
+	for _, e := range exceptions {
+		exact[CaseFoldNormalizer(e)] = e
+	}
+
+	return exact
+}
+
+// caser splits a string into words and rebuilds it with recased words.
+type caser struct {
+	input     string
+	exact     map[string]string
+	allUpper  bool
+	fragments []string
+}
+
+// casedWord is a word of the input without its surrounding punctuation.
+type casedWord struct {
+	core string
+	// index is the position of the core in caser.fragments.
+	index int
+	// afterBreak is set for the first word after a colon or a dash.
+	afterBreak bool
+	// sentenceStart is set for the first word of a sentence.
+	sentenceStart bool
+}
+
+func newCaser(input string, exact map[string]string) *caser {
+	hasUpper, hasLower := false, false
+	for _, r := range input {
+		hasUpper = hasUpper || unicode.IsUpper(r)
+		hasLower = hasLower || unicode.IsLower(r)
+	}
+
+	return &caser{
+		input:    input,
+		exact:    exact,
+		allUpper: hasUpper && !hasLower,
+	}
+}
+
+// words splits the input into fragments of text between words and the words
+// themselves, and returns the words.
+func (c *caser) words() []casedWord {
+	var words []casedWord
+
+	afterBreak, sentenceStart := false, true
+	rest := c.input
+	for rest != "" {
+		// Everything up to the next letter or digit is kept as it is.
+		i := strings.IndexFunc(rest, isWordRune)
+		if i < 0 {
+			c.fragments = append(c.fragments, rest)
+			break
+		}
+		gap := rest[:i]
+		c.fragments = append(c.fragments, gap)
+		if strings.ContainsAny(gap, ":—–") || strings.Contains(gap, " - ") {
+			afterBreak = true
+		}
+		if strings.ContainsAny(gap, ".!?¿¡") {
+			sentenceStart = true
+		}
+		rest = rest[i:]
+
+		// A word runs up to the next white space, without trailing punctuation.
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		core := strings.TrimRightFunc(rest[:end], func(r rune) bool { return !isWordRune(r) })
+		words = append(words, casedWord{
+			core:          core,
+			index:         len(c.fragments),
+			afterBreak:    afterBreak,
+			sentenceStart: sentenceStart,
+		})
+		c.fragments = append(c.fragments, core)
+		rest = rest[len(core):]
+		afterBreak, sentenceStart = false, false
+	}
+
+	return words
+}
+
+// fixed returns how the word must be written when it is an exception or
+// must be kept as it is. Capitalized words are kept when capitalized is set.
+func (c *caser) fixed(word string, capitalized bool) (string, bool) {
+	if e, ok := c.exact[CaseFoldNormalizer(word)]; ok {
+		return e, true
+	}
+	if c.allUpper {
+		return "", false
+	}
+
+	for i, r := range word {
+		// Uppercase after the first letter: an acronym or "iPhone".
+		if unicode.IsUpper(r) && (i > 0 || capitalized) {
+			return word, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *caser) set(w casedWord, core string) {
+	c.fragments[w.index] = core
+}
+
+// String returns the rebuilt string.
+func (c *caser) String() string {
+	return strings.Join(c.fragments, "")
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// capitalize writes the first letter of the word in title case and the rest
+// in lowercase.
+func capitalize(word string) string {
+	i := strings.IndexFunc(word, unicode.IsLetter)
+	if i < 0 {
+		return word
+	}
+
+	r, size := utf8.DecodeRuneInString(word[i:])
+	first, ok := specialTitleTable[r]
+	if !ok {
+		first = string(unicode.ToTitle(r))
+	}
+
+	return toLower(word[:i], LocaleDefault) + first + toLower(word[i+size:], LocaleDefault)
+}
@@ -0,0 +1,67 @@
+package textn8r
+
+import (
+	"testing"
+)
+
+func TestTitleCaseNormalizer(t *testing.T) {
+	tests := []struct {
+		style      TitleCaseStyle
+		exceptions []string
+		input      string
+		expected   string
+	}{
+		{TitleCaseAP, nil, "how to create amazing web apps in 2023!", "How to Create Amazing Web Apps in 2023!"},
+		{TitleCaseAP, nil, "the lord of the rings", "The Lord of the Rings"},
+		{TitleCaseAP, nil, "THE LORD OF THE RINGS", "The Lord of the Rings"},
+		{TitleCaseAP, nil, "what are you looking for", "What Are You Looking For"},
+		{TitleCaseAP, nil, "a walk through the park", "A Walk Through the Park"},
+		{TitleCaseChicago, nil, "a walk through the park", "A Walk through the Park"},
+		{TitleCaseAPA, nil, "a walk through the park", "A Walk Through the Park"},
+		{TitleCaseChicago, nil, "the war: an end in sight", "The War: An End in Sight"},
+		{TitleCaseAP, nil, "state-of-the-art design", "State-of-the-Art Design"},
+		{TitleCaseAP, nil, "self-driving cars", "Self-Driving Cars"},
+		{TitleCaseAP, nil, "don't stop believin'", "Don't Stop Believin'"},
+		{TitleCaseAP, nil, "why NASA uses the iPhone", "Why NASA Uses the iPhone"},
+		{TitleCaseAP, nil, "  (quoted) \"words\"  ", "  (Quoted) \"Words\"  "},
+		{TitleCaseAP, []string{"NASA", "McDonald"}, "NASA VISITS MCDONALD", "NASA Visits McDonald"},
+		{TitleCaseAP, []string{"of"}, "the sound of music", "The Sound of Music"},
+		{TitleCaseAP, []string{"Jean-Luc"}, "jean-luc on the bridge", "Jean-Luc on the Bridge"},
+		{TitleCaseAP, nil, "ﬁnal fantasy", "Final Fantasy"},
+		{TitleCaseSpanish, nil, "cien años de soledad", "Cien años de soledad"},
+		{TitleCaseSpanish, nil, "EL AMOR EN LOS TIEMPOS DEL CÓLERA", "El amor en los tiempos del cólera"},
+		{TitleCaseSpanish, nil, "viaje a Buenos Aires", "Viaje a Buenos Aires"},
+		{TitleCaseSpanish, []string{"Madrid"}, "HISTORIA DE MADRID", "Historia de Madrid"},
+		{TitleCaseAP, nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		result := TitleCaseNormalizer(tt.style, tt.exceptions...).Apply(tt.input)
+		if result != tt.expected {
+			t.Errorf("TitleCaseNormalizer(%s, %q).Apply(%q) = %q; want %q", tt.style, tt.exceptions, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestSentenceCaseNormalizer(t *testing.T) {
+	tests := []struct {
+		exceptions []string
+		input      string
+		expected   string
+	}{
+		{nil, "BREAKING NEWS. MORE SOON", "Breaking news. More soon"},
+		{nil, "The Quick Brown Fox", "The quick brown fox"},
+		{nil, "is it true? yes! it is.", "Is it true? Yes! It is."},
+		{nil, "the NASA mission uses an iPhone", "The NASA mission uses an iPhone"},
+		{[]string{"I", "Paris"}, "I LOVE PARIS. DO I?", "I love Paris. Do I?"},
+		{nil, "¿QUÉ PASA? ¡NADA!", "¿Qué pasa? ¡Nada!"},
+		{nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		result := SentenceCaseNormalizer(tt.exceptions...).Apply(tt.input)
+		if result != tt.expected {
+			t.Errorf("SentenceCaseNormalizer(%q).Apply(%q) = %q; want %q", tt.exceptions, tt.input, result, tt.expected)
+		}
+	}
+}
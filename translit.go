@@ -0,0 +1,179 @@
+package textn8r
+
+//go:generate go run maketranslit.go
+
+import (
+	"bytes"
+	"compress/zlib"
+	_ "embed"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// translitData is the compressed transliteration table of the Basic
+// Multilingual Plane generated by maketranslit.go. The data comes from the
+// Unidecode project, see maketranslit.go for details.
+//
+//go:embed translit.bin
+var translitData []byte
+
+var (
+	translitOnce sync.Once
+	// translitText holds every transliteration back to back.
+	translitText string
+	// translitOffsets holds where the transliteration of each code point
+	// starts in translitText; the one of r ends at translitOffsets[r+1].
+	translitOffsets []uint32
+	// translitMapped has a bit set for every code point with a transliteration.
+	translitMapped []uint64
+)
+
+// translitOverrides replaces Unidecode entries that are empty or unhelpful.
+var translitOverrides = map[rune]string{
+	'€': "EUR",
+	'£': "GBP",
+	'™': "TM",
+	'℠': "SM",
+	'₹': "Rs",
+	'₽': "RUB",
+	'₿': "BTC",
+	'−': "-",
+}
+
+func loadTransliterations() {
+	r, err := zlib.NewReader(bytes.NewReader(translitData))
+	if err != nil {
+		panic("textn8r: corrupt transliteration table: " + err.Error())
+	}
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		panic("textn8r: corrupt transliteration table: " + err.Error())
+	}
+
+	var text strings.Builder
+	text.Grow(len(raw))
+	offsets := make([]uint32, 0x10001)
+	mapped := make([]uint64, 0x10000/64)
+	for cp, i := 0, 0; cp < 0x10000; cp++ {
+		offsets[cp] = uint32(text.Len())
+		n := int(raw[i])
+		i++
+		if n == 0xFF {
+			continue
+		}
+		mapped[cp/64] |= 1 << (cp % 64)
+		text.Write(raw[i : i+n])
+		i += n
+	}
+	offsets[0x10000] = uint32(text.Len())
+
+	translitText = text.String()
+	translitOffsets = offsets
+	translitMapped = mapped
+}
+
+// transliterate returns the ASCII transliteration of r and whether there is one.
+func transliterate(r rune) (string, bool) {
+	if r < utf8.RuneSelf {
+		return string(r), true
+	}
+	if s, ok := translitOverrides[r]; ok {
+		return s, true
+	}
+
+	if r < 0x10000 {
+		translitOnce.Do(loadTransliterations)
+		if translitMapped[r/64]&(1<<(r%64)) != 0 {
+			return translitText[translitOffsets[r]:translitOffsets[r+1]], true
+		}
+	}
+
+	// Characters outside the table, such as mathematical alphanumerics,
+	// often decompose into characters inside it.
+	d, ok := compatibilityDecompositionTable[r]
+	if !ok {
+		d, ok = canonicalDecompositionTable[r]
+	}
+	if !ok {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, c := range d {
+		s, ok := transliterate(c)
+		if !ok {
+			return "", false
+		}
+		b.WriteString(s)
+	}
+
+	return b.String(), true
+}
+
+// TransliterateToASCIINormalizer transliterates the input string to ASCII.
+// Every rune is replaced with a best-effort ASCII approximation covering Latin, Greek,
+// Cyrillic, Arabic, Hebrew, CJK, symbols and more, so "Москва" becomes "Moskva" and "北京"
+// becomes "Bei Jing". Runes without an approximation, and invalid UTF-8 bytes, are replaced
+// with the fallback string. Non-ASCII bytes in the fallback are ignored, so the output is
+// always ASCII.
+func TransliterateToASCIINormalizer(fallback string) Normalizer {
+	fallback = strings.Map(func(r rune) rune {
+		if r >= utf8.RuneSelf {
+			return -1
+		}
+		return r
+	}, fallback)
+
+	// The space ending ideographs waits for the next word, on the same line.
+	return WithScope(func(input string) string {
+		return transliterateToASCII(input, fallback)
+	}, ScopeLine)
+}
+
+func transliterateToASCII(input, fallback string) string {
+	i := 0
+	for i < len(input) && input[i] < utf8.RuneSelf {
+		i++
+	}
+	if i == len(input) {
+		return input
+	}
+
+	var b strings.Builder
+	b.Grow(len(input))
+	b.WriteString(input[:i])
+
+	// Unidecode ends ideographs with a space to separate syllables. The space
+	// is only written when another word follows.
+	pendingSpace := false
+	for i < len(input) {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		i += size
+
+		s, ok := transliterate(r)
+		if !ok || (r == utf8.RuneError && size == 1) {
+			s = fallback
+		}
+		if s == "" {
+			continue
+		}
+
+		if pendingSpace && isASCIIAlphanumeric(s[0]) {
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		if len(s) > 1 && s[len(s)-1] == ' ' {
+			s = s[:len(s)-1]
+			pendingSpace = true
+		}
+		b.WriteString(s)
+	}
+
+	return b.String()
+}
+
+func isASCIIAlphanumeric(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,192 @@
+package textn8r
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// profileFiles holds the built-in transliteration profiles, one file per
+// language named after its language subtag, e.g. "de.txt". They are read in
+// the order of their names, so a profile can only inherit the rules of one
+// whose name comes first.
+//
+//go:embed profiles/*.txt
+var profileFiles embed.FS
+
+// TransliterationProfile maps characters to the replacement a language
+// expects, such as "ä" to "ae" in German. A character mapped to itself is
+// kept as it is instead of being folded.
+type TransliterationProfile map[rune]string
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = map[Locale]TransliterationProfile{}
+)
+
+func init() {
+	entries, err := fs.Glob(profileFiles, "profiles/*.txt")
+	if err != nil {
+		panic(err)
+	}
+	for _, name := range entries {
+		f, err := profileFiles.Open(name)
+		if err != nil {
+			panic(err)
+		}
+		profile, err := ParseTransliterationProfile(f)
+		f.Close()
+		if err != nil {
+			panic(fmt.Sprintf("textn8r: %s: %v", name, err))
+		}
+		profiles[Locale(strings.TrimSuffix(path.Base(name), ".txt"))] = profile
+	}
+}
+
+// ParseTransliterationProfile reads a profile in the format of the built-in
+// profiles. Every line holds a single character followed by its replacement,
+// separated by white space. A missing replacement removes the character.
+// Blank lines and lines starting with '#' are ignored. A line "inherit" followed
+// by a language, before the rules, starts from the rules of the profile
+// registered for it, which the following rules can replace.
+//
+//	# German
+//	ä ae
+//	ß ss
+func ParseTransliterationProfile(r io.Reader) (TransliterationProfile, error) {
+	profile := TransliterationProfile{}
+	defined := map[rune]bool{}
+	inherits := false
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if fields[0] == "inherit" {
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: want inherit and a language, got %q", line, text)
+			}
+			if inherits || len(defined) > 0 {
+				return nil, fmt.Errorf("line %d: inherit must come once, before the rules", line)
+			}
+			inherits = true
+			profilesMu.RLock()
+			inherited, ok := profiles[Locale(fields[1]).Language()]
+			profilesMu.RUnlock()
+			if !ok {
+				return nil, fmt.Errorf("line %d: no profile for %q", line, fields[1])
+			}
+			for char, replacement := range inherited {
+				profile[char] = replacement
+			}
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: want a character and its replacement, got %q", line, text)
+		}
+		char, size := utf8.DecodeRuneInString(fields[0])
+		if size != len(fields[0]) || char == utf8.RuneError {
+			return nil, fmt.Errorf("line %d: %q is not a single character", line, fields[0])
+		}
+		if defined[char] {
+			return nil, fmt.Errorf("line %d: duplicate rule for %q", line, fields[0])
+		}
+		defined[char] = true
+
+		replacement := ""
+		if len(fields) == 2 {
+			replacement = fields[1]
+		}
+		profile[char] = replacement
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// RegisterTransliterationProfile makes the profile available to
+// TransliterateNormalizer for the language of the locale, replacing the
+// built-in profile if there is one. Normalizers created before the call keep
+// the profile they were created with.
+func RegisterTransliterationProfile(locale Locale, profile TransliterationProfile) {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+
+	profiles[locale.Language()] = profile
+}
+
+// TransliterateNormalizer folds the input string the way the language of the
+// locale expects. The rules of the language profile are applied first, so
+// German "Müller" becomes "Mueller" and Danish "Ålborg" becomes "Aalborg"; the
+// remaining characters are folded as ReplaceAccentsNormalizer does. Spanish
+// keeps "ñ". Locales without a profile get the plain accent folding.
+func TransliterateNormalizer(locale Locale) Normalizer {
+	profilesMu.RLock()
+	profile := profiles[locale.Language()]
+	profilesMu.RUnlock()
+
+	// Expansions follow the case of the letters around them, on the same line.
+	return WithScope(func(input string) string {
+		return transliterateProfile(input, profile)
+	}, ScopeLine)
+}
+
+func transliterateProfile(input string, profile TransliterationProfile) string {
+	if len(profile) == 0 {
+		return foldAccents(input)
+	}
+
+	// Rules are written for precomposed characters.
+	input = NFCNormalizer(input)
+
+	var b strings.Builder
+	start := 0
+	prev := utf8.RuneError
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		replacement, ok := profile[r]
+		if !ok {
+			prev = r
+			i += size
+			continue
+		}
+
+		if start == 0 {
+			b.Grow(len(input) + len(input)/8)
+		}
+		b.WriteString(foldAccents(input[start:i]))
+
+		// An uppercase letter expanding to several letters is written in
+		// uppercase inside uppercase words: "MÜLLER" becomes "MUELLER".
+		if unicode.IsUpper(r) && utf8.RuneCountInString(replacement) > 1 {
+			next, _ := utf8.DecodeRuneInString(input[i+size:])
+			if unicode.IsUpper(prev) || unicode.IsUpper(next) {
+				replacement = strings.ToUpper(replacement)
+			}
+		}
+		b.WriteString(replacement)
+
+		prev = r
+		i += size
+		start = i
+	}
+	if start == 0 {
+		return foldAccents(input)
+	}
+	b.WriteString(foldAccents(input[start:]))
+
+	return b.String()
+}
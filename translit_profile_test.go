@@ -0,0 +1,105 @@
+package textn8r
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransliterateNormalizer(t *testing.T) {
+	tests := []struct {
+		locale   Locale
+		input    string
+		expected string
+	}{
+		{LocaleGerman, "Müller", "Mueller"},
+		{LocaleGerman, "MÜLLER", "MUELLER"},
+		{LocaleGerman, "Ärger über Öl", "Aerger ueber Oel"},
+		{LocaleGerman, "Straße, STRAẞE", "Strasse, STRASSE"},
+		{LocaleGerman, "Müller", "Mueller"},
+		{LocaleGerman, "Café Zoë", "Cafe Zoe"},
+		{"de-AT", "Grüß Gott", "Gruess Gott"},
+		{LocaleDanish, "Ålborg, Ærø, søster", "Aalborg, Aeroe, soester"},
+		{LocaleNorwegian, "Bodø, Tromsø", "Bodoe, Tromsoe"},
+		{"nn", "Ålesund", "Aalesund"},
+		{"no-NO", "Bodø", "Bodoe"},
+		{LocaleSwedish, "Malmö, Åre, Västerås", "Malmoe, Aare, Vaesteraas"},
+		{LocaleSpanish, "Señor Muñoz, canción", "Señor Muñoz, cancion"},
+		{LocaleTurkish, "İstanbul, Iğdır, Çeşme", "Istanbul, Igdir, Cesme"},
+		{LocaleDefault, "Müller, Ålborg, niño", "Muller, Alborg, nino"},
+		{"xx", "Müller", "Muller"},
+		{LocaleGerman, "plain ascii", "plain ascii"},
+	}
+
+	for _, tt := range tests {
+		result := TransliterateNormalizer(tt.locale).Apply(tt.input)
+		if result != tt.expected {
+			t.Errorf("TransliterateNormalizer(%q).Apply(%q) = %q; want %q", tt.locale, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestParseTransliterationProfile(t *testing.T) {
+	profile, err := ParseTransliterationProfile(strings.NewReader("# Test\n\nœ oe\n† \n"))
+	if err != nil {
+		t.Fatalf("ParseTransliterationProfile() error = %v", err)
+	}
+	if len(profile) != 2 || profile['œ'] != "oe" || profile['†'] != "" {
+		t.Errorf("ParseTransliterationProfile() = %q; want œ→oe and † removed", profile)
+	}
+
+	// Rules replace the inherited ones.
+	profile, err = ParseTransliterationProfile(strings.NewReader("# Test\ninherit de-CH\nä a\n€ euro\n"))
+	if err != nil {
+		t.Fatalf("ParseTransliterationProfile() with inherit error = %v", err)
+	}
+	if profile['ä'] != "a" || profile['ö'] != "oe" || profile['€'] != "euro" {
+		t.Errorf("ParseTransliterationProfile() with inherit = %q; want ä→a, ö→oe and €→euro", profile)
+	}
+
+	invalid := []string{
+		"ab c\n",
+		"a b c\n",
+		"a b\na c\n",
+		"inherit\n",
+		"inherit xx\n",
+		"a b\ninherit de\n",
+		"inherit de\ninherit da\n",
+	}
+	for _, input := range invalid {
+		if _, err := ParseTransliterationProfile(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseTransliterationProfile(%q) error = nil; want an error", input)
+		}
+	}
+}
+
+func TestRegisterTransliterationProfile(t *testing.T) {
+	RegisterTransliterationProfile("x-test", TransliterationProfile{'ø': "oe", '€': "euro"})
+	t.Cleanup(func() {
+		profilesMu.Lock()
+		delete(profiles, "x")
+		profilesMu.Unlock()
+	})
+
+	result := TransliterateNormalizer("x-other").Apply("5€ smørrebrød")
+	if result != "5euro smoerrebroed" {
+		t.Errorf("TransliterateNormalizer(%q).Apply() = %q; want %q", "x-other", result, "5euro smoerrebroed")
+	}
+}
+
+func TestLocaleLanguage(t *testing.T) {
+	tests := []struct {
+		locale   Locale
+		expected Locale
+	}{
+		{"de", "de"},
+		{"de-AT", "de"},
+		{"DE_at", "de"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if result := tt.locale.Language(); result != tt.expected {
+			t.Errorf("Locale(%q).Language() = %q; want %q", tt.locale, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package textn8r
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTransliterateToASCIINormalizer(t *testing.T) {
+	tests := []struct {
+		input    string
+		fallback string
+		expected string
+	}{
+		{"hello world", "", "hello world"},
+		{"Łódź, Straße", "", "Lodz, Strasse"},
+		{"Æsir þing", "", "AEsir thing"},
+		{"Москва", "", "Moskva"},
+		{"Київ", "", "Kiyiv"},
+		{"Αθήνα", "", "Athena"},
+		{"שלום", "", "shlvm"},
+		{"北京", "", "Bei Jing"},
+		{"北京市, China", "", "Bei Jing Shi, China"},
+		{"こんにちは", "", "konnichiha"},
+		{"서울", "", "seoul"},
+		{"“quoted” — dash…", "", "\"quoted\" -- dash..."},
+		{"€100 ™", "", "EUR100 TM"},
+		{"𝐇𝐞𝐥𝐥𝐨", "", "Hello"},
+		{"ﬁ ½", "", "fi 1/2"},
+		{"café", "", "cafe"},
+		{"😀 ok", "", " ok"},
+		{"😀 ok", "?", "? ok"},
+		{"a\xffb", "?", "a?b"},
+		{"😀", "¿?", "?"},
+	}
+
+	for _, tt := range tests {
+		result := TransliterateToASCIINormalizer(tt.fallback).Apply(tt.input)
+		if result != tt.expected {
+			t.Errorf("TransliterateToASCIINormalizer(%q).Apply(%q) = %q; want %q", tt.fallback, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTransliterateToASCIINormalizerOutputIsASCII(t *testing.T) {
+	normalizer := TransliterateToASCIINormalizer("¿")
+	for r := rune(0); r <= utf8.MaxRune; r += 7 {
+		result := normalizer(string(r))
+		for i := 0; i < len(result); i++ {
+			if result[i] >= utf8.RuneSelf {
+				t.Fatalf("TransliterateToASCIINormalizer(%U) = %q; want ASCII", r, result)
+			}
+		}
+	}
+}
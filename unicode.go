@@ -0,0 +1,360 @@
+package textn8r
+
+//go:generate go run maketables.go
+
+import (
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// Form identifies one of the Unicode normalization forms defined in UAX #15.
+type Form int
+
+const (
+	// FormNFC is canonical decomposition followed by canonical composition.
+	FormNFC Form = iota
+	// FormNFD is canonical decomposition.
+	FormNFD
+	// FormNFKC is compatibility decomposition followed by canonical composition.
+	FormNFKC
+	// FormNFKD is compatibility decomposition.
+	FormNFKD
+)
+
+// String returns the name of the normalization form, e.g. "NFC".
+func (f Form) String() string {
+	switch f {
+	case FormNFC:
+		return "NFC"
+	case FormNFD:
+		return "NFD"
+	case FormNFKC:
+		return "NFKC"
+	case FormNFKD:
+		return "NFKD"
+	}
+
+	return "Form(" + strconv.Itoa(int(f)) + ")"
+}
+
+// QuickCheckResult is the answer of the Unicode quick check algorithm.
+type QuickCheckResult int
+
+const (
+	// QuickCheckYes means the input is known to be in the normalization form.
+	QuickCheckYes QuickCheckResult = iota
+	// QuickCheckNo means the input is known not to be in the normalization form.
+	QuickCheckNo
+	// QuickCheckMaybe means the input has to be normalized to find out.
+	QuickCheckMaybe
+)
+
+// NFCNormalizer converts the input string to Unicode Normalization Form C.
+func NFCNormalizer(input string) string {
+	return FormNFC.Apply(input)
+}
+
+// NFDNormalizer converts the input string to Unicode Normalization Form D.
+func NFDNormalizer(input string) string {
+	return FormNFD.Apply(input)
+}
+
+// NFKCNormalizer converts the input string to Unicode Normalization Form KC.
+func NFKCNormalizer(input string) string {
+	return FormNFKC.Apply(input)
+}
+
+// NFKDNormalizer converts the input string to Unicode Normalization Form KD.
+func NFKDNormalizer(input string) string {
+	return FormNFKD.Apply(input)
+}
+
+// Apply returns the input string converted to the normalization form.
+// Input that is already normalized is returned unchanged, and bytes that are
+// not valid UTF-8 are copied through as they are.
+func (f Form) Apply(input string) string {
+	if f.QuickCheck(input) == QuickCheckYes {
+		return input
+	}
+
+	return string(f.appendString(make([]byte, 0, len(input)+len(input)/4), input))
+}
+
+// runeBuffers holds the buffers appendString decomposes into.
+var runeBuffers = sync.Pool{
+	New: func() any { return new([]rune) },
+}
+
+// appendString appends the input string converted to the normalization form
+// to dst.
+func (f Form) appendString(dst []byte, input string) []byte {
+	if f.QuickCheck(input) == QuickCheckYes {
+		return append(dst, input...)
+	}
+
+	buf := runeBuffers.Get().(*[]rune)
+	runes := *buf
+	start := 0
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		if r == utf8.RuneError && size == 1 {
+			runes = f.appendNormalized(runes[:0], input[start:i])
+			dst = appendRunes(dst, runes)
+			dst = append(dst, input[i])
+			start = i + 1
+		}
+		i += size
+	}
+	runes = f.appendNormalized(runes[:0], input[start:])
+	dst = appendRunes(dst, runes)
+	*buf = runes
+	runeBuffers.Put(buf)
+
+	return dst
+}
+
+// IsNormalized reports whether the input string is in the normalization form.
+func (f Form) IsNormalized(input string) bool {
+	switch f.QuickCheck(input) {
+	case QuickCheckYes:
+		return true
+	case QuickCheckNo:
+		return false
+	}
+
+	return f.Apply(input) == input
+}
+
+// QuickCheck runs the quick check algorithm of UAX #15 on the input string.
+// It never allocates and answers QuickCheckMaybe only when the input contains
+// characters that may combine with their predecessor.
+func (f Form) QuickCheck(input string) QuickCheckResult {
+	result := QuickCheckYes
+	var last uint8
+	for _, r := range input {
+		if r < 0xA0 {
+			last = 0
+			continue
+		}
+
+		class := combiningClass(r)
+		if class != 0 && last > class {
+			return QuickCheckNo
+		}
+
+		switch f.quickCheckRune(r) {
+		case QuickCheckNo:
+			return QuickCheckNo
+		case QuickCheckMaybe:
+			result = QuickCheckMaybe
+		}
+		last = class
+	}
+
+	return result
+}
+
+func (f Form) quickCheckRune(r rune) QuickCheckResult {
+	if isHangulSyllable(r) {
+		if f == FormNFD || f == FormNFKD {
+			return QuickCheckNo
+		}
+		return QuickCheckYes
+	}
+
+	if _, ok := canonicalDecompositionTable[r]; ok {
+		if f == FormNFD || f == FormNFKD || !primaryComposites[r] {
+			return QuickCheckNo
+		}
+	}
+
+	if f == FormNFKC || f == FormNFKD {
+		if _, ok := compatibilityDecompositionTable[r]; ok {
+			return QuickCheckNo
+		}
+	}
+
+	if (f == FormNFC || f == FormNFKC) && combinesBackward(r) {
+		return QuickCheckMaybe
+	}
+
+	return QuickCheckYes
+}
+
+// appendNormalized appends the normalized runes of a valid UTF-8 string to dst.
+func (f Form) appendNormalized(dst []rune, s string) []rune {
+	compatible := f == FormNFKC || f == FormNFKD
+	for _, r := range s {
+		dst = appendDecomposed(dst, r, compatible)
+	}
+	reorder(dst)
+
+	if f == FormNFC || f == FormNFKC {
+		dst = compose(dst)
+	}
+
+	return dst
+}
+
+// Hangul syllable constants from section 3.12 of the Unicode Standard.
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+func isHangulSyllable(r rune) bool {
+	return r >= hangulSBase && r < hangulSBase+hangulSCount
+}
+
+// appendDecomposed appends the full decomposition of r to dst.
+func appendDecomposed(dst []rune, r rune, compatible bool) []rune {
+	if r < 0xA0 {
+		return append(dst, r)
+	}
+
+	if isHangulSyllable(r) {
+		s := r - hangulSBase
+		dst = append(dst, hangulLBase+s/hangulNCount, hangulVBase+(s%hangulNCount)/hangulTCount)
+		if t := s % hangulTCount; t != 0 {
+			dst = append(dst, hangulTBase+t)
+		}
+		return dst
+	}
+
+	if compatible {
+		if d, ok := compatibilityDecompositionTable[r]; ok {
+			for _, c := range d {
+				dst = append(dst, c)
+			}
+			return dst
+		}
+	}
+
+	if d, ok := canonicalDecompositionTable[r]; ok {
+		for _, c := range d {
+			dst = append(dst, c)
+		}
+		return dst
+	}
+
+	return append(dst, r)
+}
+
+// reorder applies the canonical ordering algorithm: every run of non-starters
+// is stably sorted by canonical combining class.
+func reorder(runes []rune) {
+	for i := 1; i < len(runes); i++ {
+		class := combiningClass(runes[i])
+		if class == 0 {
+			continue
+		}
+		for j := i; j > 0; j-- {
+			prev := combiningClass(runes[j-1])
+			if prev <= class {
+				break
+			}
+			runes[j], runes[j-1] = runes[j-1], runes[j]
+		}
+	}
+}
+
+// compose applies the canonical composition algorithm in place.
+func compose(runes []rune) []rune {
+	if len(runes) == 0 {
+		return runes
+	}
+
+	starter := 0
+	last := int(combiningClass(runes[0]))
+	if last != 0 {
+		// No starter yet, nothing before it can combine.
+		last = 256
+	}
+
+	n := 1
+	for _, r := range runes[1:] {
+		class := int(combiningClass(r))
+		if last < class || last == 0 {
+			if c, ok := composePair(runes[starter], r); ok {
+				runes[starter] = c
+				continue
+			}
+		}
+
+		if class == 0 {
+			starter = n
+		}
+		last = class
+		runes[n] = r
+		n++
+	}
+
+	return runes[:n]
+}
+
+// composePair returns the primary composite of a and b, if there is one.
+func composePair(a, b rune) (rune, bool) {
+	if a >= hangulLBase && a < hangulLBase+hangulLCount && b >= hangulVBase && b < hangulVBase+hangulVCount {
+		return hangulSBase + ((a-hangulLBase)*hangulVCount+(b-hangulVBase))*hangulTCount, true
+	}
+
+	if isHangulSyllable(a) && (a-hangulSBase)%hangulTCount == 0 && b > hangulTBase && b < hangulTBase+hangulTCount {
+		return a + (b - hangulTBase), true
+	}
+
+	c, ok := compositionTable[uint64(a)<<32|uint64(b)]
+	return c, ok
+}
+
+// combiningClass returns the canonical combining class of r.
+func combiningClass(r rune) uint8 {
+	if r < 0x300 {
+		return 0
+	}
+
+	return combiningClassTable[r]
+}
+
+// combinesBackward reports whether r can be the second rune of a primary
+// composite, which makes it NFC_QC=Maybe.
+func combinesBackward(r rune) bool {
+	if r >= hangulVBase && r < hangulVBase+hangulVCount {
+		return true
+	}
+	if r > hangulTBase && r < hangulTBase+hangulTCount {
+		return true
+	}
+
+	return compositionSeconds[r]
+}
+
+var (
+	// primaryComposites holds every rune produced by compositionTable.
+	primaryComposites = map[rune]bool{}
+	// compositionSeconds holds every rune that is the second rune of a
+	// primary composite.
+	compositionSeconds = map[rune]bool{}
+)
+
+func init() {
+	for pair, c := range compositionTable {
+		primaryComposites[c] = true
+		compositionSeconds[rune(pair&0xFFFFFFFF)] = true
+	}
+}
+
+func appendRunes(dst []byte, runes []rune) []byte {
+	for _, r := range runes {
+		dst = utf8.AppendRune(dst, r)
+	}
+
+	return dst
+}
@@ -0,0 +1,259 @@
+package textn8r
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNFCNormalizer(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hello world", "hello world"},
+		{"cafe\u0301", "caf\u00e9"},
+		{"caf\u00e9", "caf\u00e9"},
+		{"\u212b", "\u00c5"},              // ANGSTROM SIGN is a singleton
+		{"a\u0323\u0302", "\u1ead"},       // reordered before composing
+		{"a\u0302\u0323", "\u1ead"},       // non-starters in the wrong order
+		{"\u0915\u093c", "\u0915\u093c"},  // composition exclusion
+		{"\u1100\u1161\u11a8", "\uac01"},  // Hangul LVT
+		{"\ufb01", "\ufb01"},              // compatibility characters are kept
+		{"\u0301a", "\u0301a"},            // leading combining mark
+		{"e\xff\u0301", "e\xff\u0301"},    // invalid UTF-8 is kept and blocks composition
+		{"A\u0308\u0301", "\u00c4\u0301"}, // no composite for A-diaeresis + acute
+	}
+
+	for _, tt := range tests {
+		result := NFCNormalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("NFCNormalizer(%+q) = %+q; want %+q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNFDNormalizer(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hello world", "hello world"},
+		{"caf\u00e9", "cafe\u0301"},
+		{"\u1ead", "a\u0323\u0302"},
+		{"a\u0302\u0323", "a\u0323\u0302"},
+		{"\uac01", "\u1100\u1161\u11a8"},
+		{"\u1e9b\u0323", "\u017f\u0323\u0307"},
+		{"\ufb01", "\ufb01"},
+	}
+
+	for _, tt := range tests {
+		result := NFDNormalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("NFDNormalizer(%+q) = %+q; want %+q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNFKCNormalizer(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hello world", "hello world"},
+		{"\ufb01", "fi"},
+		{"\u2460", "1"},
+		{"x\u00b2", "x2"},
+		{"\uff21\uff22\uff23", "ABC"},
+		{"\u1e9b\u0323", "\u1e69"},
+		{"\u00a0", " "},
+	}
+
+	for _, tt := range tests {
+		result := NFKCNormalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("NFKCNormalizer(%+q) = %+q; want %+q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNFKDNormalizer(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hello world", "hello world"},
+		{"\ufb01", "fi"},
+		{"\u1e9b\u0323", "s\u0323\u0307"},
+		{"\u00bd", "1\u20442"},
+	}
+
+	for _, tt := range tests {
+		result := NFKDNormalizer(tt.input)
+		if result != tt.expected {
+			t.Errorf("NFKDNormalizer(%+q) = %+q; want %+q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestFormQuickCheck(t *testing.T) {
+	tests := []struct {
+		form     Form
+		input    string
+		expected QuickCheckResult
+	}{
+		{FormNFC, "hello", QuickCheckYes},
+		{FormNFC, "caf\u00e9", QuickCheckYes},
+		{FormNFC, "cafe\u0301", QuickCheckMaybe},
+		{FormNFC, "\u212b", QuickCheckNo},
+		{FormNFC, "a\u0302\u0323", QuickCheckNo},
+		{FormNFC, "\uac01", QuickCheckYes},
+		{FormNFD, "caf\u00e9", QuickCheckNo},
+		{FormNFD, "cafe\u0301", QuickCheckYes},
+		{FormNFD, "\uac01", QuickCheckNo},
+		{FormNFKC, "\ufb01", QuickCheckNo},
+		{FormNFKC, "a\u0308", QuickCheckMaybe},
+		{FormNFKD, "\ufb01", QuickCheckNo},
+		{FormNFKD, "abc", QuickCheckYes},
+	}
+
+	for _, tt := range tests {
+		result := tt.form.QuickCheck(tt.input)
+		if result != tt.expected {
+			t.Errorf("%v.QuickCheck(%+q) = %v; want %v", tt.form, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestFormIsNormalized(t *testing.T) {
+	tests := []struct {
+		form     Form
+		input    string
+		expected bool
+	}{
+		{FormNFC, "caf\u00e9", true},
+		{FormNFC, "cafe\u0301", false},
+		{FormNFC, "\u0915\u093c", true},
+		{FormNFD, "cafe\u0301", true},
+		{FormNFKC, "\ufb01", false},
+		{FormNFKD, "fi", true},
+	}
+
+	for _, tt := range tests {
+		result := tt.form.IsNormalized(tt.input)
+		if result != tt.expected {
+			t.Errorf("%v.IsNormalized(%+q) = %v; want %v", tt.form, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestFormApplyDoesNotAllocateWhenNormalized(t *testing.T) {
+	input := "Plain ASCII and precomposed café"
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = FormNFC.Apply(input)
+	})
+	if allocs != 0 {
+		t.Errorf("FormNFC.Apply allocated %v times; want 0", allocs)
+	}
+}
+
+// TestNormalizationConformance runs the official conformance test of UAX #15.
+// The file is fetched with "go run maketables.go -testdata". Without it the
+// test is skipped locally but fails in CI, which always fetches it.
+func TestNormalizationConformance(t *testing.T) {
+	f, err := os.Open("testdata/NormalizationTest.txt")
+	if err != nil {
+		if os.Getenv("CI") != "" {
+			t.Fatalf("conformance data not available: %v", err)
+		}
+		t.Skipf("conformance data not available: %v", err)
+	}
+	defer f.Close()
+
+	forms := []struct {
+		name string
+		fn   Normalizer
+	}{
+		{"NFC", NFCNormalizer},
+		{"NFD", NFDNormalizer},
+		{"NFKC", NFKCNormalizer},
+		{"NFKD", NFKDNormalizer},
+	}
+	// For each form, the column of the expected result for columns 1 to 5.
+	expected := map[string][5]int{
+		"NFC":  {1, 1, 1, 3, 3},
+		"NFD":  {2, 2, 2, 4, 4},
+		"NFKC": {3, 3, 3, 3, 3},
+		"NFKD": {4, 4, 4, 4, 4},
+	}
+
+	listed := map[rune]bool{}
+	part1 := false
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.HasPrefix(text, "@Part") {
+			part1 = strings.HasPrefix(text, "@Part1")
+			continue
+		}
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		var cols [5]string
+		for i := range cols {
+			cols[i] = parseCodePoints(t, fields[i])
+		}
+		if part1 {
+			r, _ := utf8.DecodeRuneInString(cols[0])
+			listed[r] = true
+		}
+
+		for _, form := range forms {
+			for i, col := range cols {
+				want := cols[expected[form.name][i]]
+				if got := form.fn(col); got != want {
+					t.Errorf("line %d: %s(%+q) = %+q; want %+q", line, form.name, col, got, want)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every character not listed in part 1 is invariant under all forms.
+	for r := rune(0); r <= utf8.MaxRune; r++ {
+		if listed[r] || (r >= 0xD800 && r <= 0xDFFF) {
+			continue
+		}
+		s := string(r)
+		for _, form := range forms {
+			if got := form.fn(s); got != s {
+				t.Errorf("%s(%+q) = %+q; want it unchanged", form.name, s, got)
+			}
+		}
+	}
+}
+
+func parseCodePoints(t *testing.T, field string) string {
+	t.Helper()
+
+	var b strings.Builder
+	for _, cp := range strings.Fields(field) {
+		v, err := strconv.ParseUint(cp, 16, 32)
+		if err != nil {
+			t.Fatalf("invalid code point %q: %v", cp, err)
+		}
+		b.WriteRune(rune(v))
+	}
+
+	return b.String()
+}
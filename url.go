@@ -0,0 +1,467 @@
+package textn8r
+
+import (
+	"errors"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultTrackingParams holds globs of the query parameters used to track
+// visitors, which do not change the resource a URL points to.
+var DefaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "dclid", "gbraid", "wbraid", "msclkid",
+	"yclid", "igshid", "mc_cid", "mc_eid", "_ga", "_hsenc", "_hsmi",
+}
+
+// URLOptions configures URLNormalizer.
+type URLOptions struct {
+	// LowerCase lowercases the scheme and the host.
+	LowerCase bool
+	// IDNAHost writes hosts with other than ASCII characters in the ASCII
+	// form of IDNA, such as "xn--bcher-kva.example".
+	IDNAHost bool
+	// RemoveDefaultPort removes ports that are the default of the scheme,
+	// such as 443 or 0443 for https, and empty ports.
+	RemoveDefaultPort bool
+	// ResolveDotSegments resolves the "." and ".." segments of the path, and
+	// writes empty paths as "/" when there is a host.
+	ResolveDotSegments bool
+	// NormalizeEncoding decodes percent-encoded unreserved characters,
+	// uppercases the hexadecimal digits of the others, and encodes the
+	// characters that cannot appear unencoded, such as spaces, non-ASCII
+	// characters and "@" in user information.
+	NormalizeEncoding bool
+	// SortQuery sorts the query parameters by name, keeping the order of the
+	// values of each name.
+	SortQuery bool
+	// RemoveParams holds globs, in the syntax of path.Match, of the query
+	// parameters to remove, such as DefaultTrackingParams.
+	RemoveParams []string
+	// RemoveFragment removes the fragment.
+	RemoveFragment bool
+}
+
+// DefaultURLOptions enables every normalization of URLNormalizer and
+// removes DefaultTrackingParams.
+var DefaultURLOptions = URLOptions{
+	LowerCase:          true,
+	IDNAHost:           true,
+	RemoveDefaultPort:  true,
+	ResolveDotSegments: true,
+	NormalizeEncoding:  true,
+	SortQuery:          true,
+	RemoveParams:       DefaultTrackingParams,
+	RemoveFragment:     true,
+}
+
+// defaultPorts holds the default port of common schemes.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+// isDefaultPort reports whether port, a string of digits, is the default port
+// of the scheme, regardless of leading zeros.
+func isDefaultPort(scheme, port string) bool {
+	defaultPort, ok := defaultPorts[strings.ToLower(scheme)]
+	return ok && strings.TrimLeft(port, "0") == defaultPort
+}
+
+// URLNormalizer returns a normalizer making absolute URLs canonical
+// following RFC 3986, so the URLs of the same resource compare equal:
+//
+//	n := URLNormalizer(DefaultURLOptions)
+//	n("HTTPS://Example.COM:443/a/./b/../c?utm_source=x&b=2&a=%7e1#top")
+//	// "https://example.com/a/c?a=~1&b=2"
+//
+// Paths, queries and user information keep their case. Input that is not an
+// absolute URL, or has invalid percent-encoding, ports or hosts, gives an
+// *InvalidInputError.
+func URLNormalizer(options URLOptions) NormalizerE {
+	for _, glob := range options.RemoveParams {
+		if _, err := path.Match(glob, ""); err != nil {
+			return func(input string) (string, error) {
+				return input, &ArgumentError{Normalizer: "url", Param: "RemoveParams", Reason: "invalid glob " + strconv.Quote(glob)}
+			}
+		}
+	}
+
+	return func(input string) (string, error) {
+		output, err := normalizeURL(strings.TrimSpace(input), &options)
+		if err != nil {
+			return input, &InvalidInputError{Input: input, Reason: err.Error()}
+		}
+		return output, nil
+	}
+}
+
+// urlParts holds the components of a URL, as in RFC 3986, section 3.
+type urlParts struct {
+	scheme       string
+	hasAuthority bool
+	userinfo     string
+	hasUserinfo  bool
+	host         string
+	port         string
+	hasPort      bool
+	path         string
+	query        string
+	hasQuery     bool
+	fragment     string
+	hasFragment  bool
+}
+
+func normalizeURL(input string, options *URLOptions) (string, error) {
+	u, err := splitURL(input)
+	if err != nil {
+		return "", err
+	}
+
+	if options.LowerCase {
+		u.scheme = strings.ToLower(u.scheme)
+	}
+
+	if options.NormalizeEncoding {
+		for _, part := range []*string{&u.userinfo, &u.host, &u.path, &u.query, &u.fragment} {
+			if *part, err = normalizePercentEncoding(*part); err != nil {
+				return "", err
+			}
+		}
+		// The host starts after the last "@", so the others belong to the
+		// user information.
+		u.userinfo = strings.ReplaceAll(u.userinfo, "@", "%40")
+	}
+
+	if u.hasAuthority {
+		if err := normalizeHost(&u, options); err != nil {
+			return "", err
+		}
+		if strings.Trim(u.port, "0123456789") != "" {
+			return "", errors.New("invalid port " + u.port)
+		}
+		if options.RemoveDefaultPort && (u.port == "" || isDefaultPort(u.scheme, u.port)) {
+			u.hasPort = false
+		}
+	}
+
+	if options.ResolveDotSegments {
+		if strings.HasPrefix(u.path, "/") {
+			u.path = removeDotSegments(u.path)
+		}
+		if u.hasAuthority && u.path == "" {
+			u.path = "/"
+		}
+	}
+
+	if u.hasQuery && (options.SortQuery || len(options.RemoveParams) > 0) {
+		u.query = normalizeQuery(u.query, options)
+		u.hasQuery = u.query != ""
+	}
+	if options.RemoveFragment {
+		u.hasFragment = false
+	}
+
+	return u.String(), nil
+}
+
+// splitURL splits an absolute URL into its components.
+func splitURL(input string) (urlParts, error) {
+	var u urlParts
+
+	rest, fragment, hasFragment := strings.Cut(input, "#")
+	u.fragment, u.hasFragment = fragment, hasFragment
+	rest, u.query, u.hasQuery = strings.Cut(rest, "?")
+
+	scheme, rest, ok := strings.Cut(rest, ":")
+	if !ok || !isURLScheme(scheme) {
+		return u, errors.New("not an absolute URL")
+	}
+	u.scheme = scheme
+
+	if !strings.HasPrefix(rest, "//") {
+		u.path = rest
+		return u, nil
+	}
+
+	u.hasAuthority = true
+	authority := rest[2:]
+	if i := strings.IndexByte(authority, '/'); i >= 0 {
+		authority, u.path = authority[:i], authority[i:]
+	}
+	if i := strings.LastIndexByte(authority, '@'); i >= 0 {
+		u.userinfo, u.hasUserinfo, authority = authority[:i], true, authority[i+1:]
+	}
+
+	// The port follows the last colon, after the brackets of IPv6 hosts.
+	end := strings.LastIndexByte(authority, ']')
+	if i := strings.LastIndexByte(authority, ':'); i > end && (end >= 0 || authority[0] != '[') {
+		authority, u.port, u.hasPort = authority[:i], authority[i+1:], true
+	}
+	u.host = authority
+
+	return u, nil
+}
+
+// isURLScheme reports whether s is a scheme: a letter followed by letters,
+// digits, "+", "-" and ".".
+func isURLScheme(s string) bool {
+	if s == "" || !isASCIILetter(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if c := s[i]; !isASCIIAlphanumeric(c) && c != '+' && c != '-' && c != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// normalizeHost lowercases the host and converts it to the ASCII form of
+// IDNA, following the options.
+func normalizeHost(u *urlParts, options *URLOptions) error {
+	if strings.HasPrefix(u.host, "[") {
+		if !strings.HasSuffix(u.host, "]") {
+			return errors.New("invalid host " + u.host)
+		}
+		if options.LowerCase {
+			u.host = strings.ToLower(u.host)
+		}
+		return nil
+	}
+
+	if options.IDNAHost {
+		// Encoded UTF-8 is decoded to be converted.
+		host := u.host
+		if strings.Contains(host, "%") {
+			decoded, err := percentDecode(host)
+			if err != nil {
+				return err
+			}
+			host = decoded
+		}
+		if !isASCII(host) {
+			ascii, err := domainToASCII(host)
+			if err != nil {
+				return errors.New("invalid host: " + err.Error())
+			}
+			u.host = ascii
+			return nil
+		}
+	}
+	if options.LowerCase {
+		// Encoded bytes keep their case, which NormalizeEncoding sets.
+		u.host = lowerOutsideEscapes(u.host)
+	}
+
+	return nil
+}
+
+// lowerOutsideEscapes lowercases the string, leaving the hexadecimal digits
+// of percent-encoded bytes alone.
+func lowerOutsideEscapes(s string) string {
+	if !strings.Contains(s, "%") {
+		return strings.ToLower(s)
+	}
+
+	var sb strings.Builder
+	for {
+		i := strings.IndexByte(s, '%')
+		if i < 0 || i+3 > len(s) {
+			sb.WriteString(strings.ToLower(s))
+			return sb.String()
+		}
+		sb.WriteString(strings.ToLower(s[:i]))
+		sb.WriteString(s[i : i+3])
+		s = s[i+3:]
+	}
+}
+
+// isUnreserved reports whether c is an unreserved character of RFC 3986,
+// which percent-encoding does not change.
+func isUnreserved(c byte) bool {
+	return isASCIIAlphanumeric(c) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// mustEscape reports whether c cannot appear unencoded in a URL.
+func mustEscape(c byte) bool {
+	return c <= ' ' || c >= 0x7f || strings.IndexByte("\"<>\\^`{|}", c) >= 0
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+
+	return 0, false
+}
+
+// normalizePercentEncoding decodes encoded unreserved characters, uppercases
+// the other escapes and encodes the characters that must be.
+func normalizePercentEncoding(s string) (string, error) {
+	const hex = "0123456789ABCDEF"
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%':
+			b, err := escapedByte(s, i)
+			if err != nil {
+				return "", err
+			}
+			if isUnreserved(b) {
+				sb.WriteByte(b)
+			} else {
+				sb.WriteByte('%')
+				sb.WriteByte(hex[b>>4])
+				sb.WriteByte(hex[b&15])
+			}
+			i += 2
+		case mustEscape(c):
+			sb.WriteByte('%')
+			sb.WriteByte(hex[c>>4])
+			sb.WriteByte(hex[c&15])
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// escapedByte returns the byte encoded at s[i:i+3].
+func escapedByte(s string, i int) (byte, error) {
+	if i+2 < len(s) {
+		hi, ok1 := unhex(s[i+1])
+		lo, ok2 := unhex(s[i+2])
+		if ok1 && ok2 {
+			return hi<<4 | lo, nil
+		}
+	}
+
+	end := min(i+3, len(s))
+	return 0, errors.New("invalid percent-encoding " + s[i:end])
+}
+
+// percentDecode decodes every escape of s.
+func percentDecode(s string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		b, err := escapedByte(s, i)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(b)
+		i += 2
+	}
+
+	return sb.String(), nil
+}
+
+// removeDotSegments resolves the "." and ".." segments of an absolute path,
+// as in RFC 3986, section 5.2.4.
+func removeDotSegments(p string) string {
+	segments := strings.Split(p[1:], "/")
+	out := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch segment {
+		case ".":
+		case "..":
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		default:
+			out = append(out, segment)
+			continue
+		}
+		// A final dot segment leaves a trailing slash.
+		if last {
+			out = append(out, "")
+		}
+	}
+
+	return "/" + strings.Join(out, "/")
+}
+
+// normalizeQuery removes the parameters matching options.RemoveParams and
+// sorts the others, following the options. Empty parameters are dropped.
+func normalizeQuery(query string, options *URLOptions) string {
+	var params []string
+	for _, param := range strings.Split(query, "&") {
+		if param == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(param, "=")
+		removed := false
+		for _, glob := range options.RemoveParams {
+			if ok, _ := path.Match(glob, name); ok {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			params = append(params, param)
+		}
+	}
+
+	if options.SortQuery {
+		sort.SliceStable(params, func(i, j int) bool {
+			a, _, _ := strings.Cut(params[i], "=")
+			b, _, _ := strings.Cut(params[j], "=")
+			return a < b
+		})
+	}
+
+	return strings.Join(params, "&")
+}
+
+// String joins the components.
+func (u *urlParts) String() string {
+	var sb strings.Builder
+	sb.WriteString(u.scheme)
+	sb.WriteByte(':')
+	if u.hasAuthority {
+		sb.WriteString("//")
+		if u.hasUserinfo {
+			sb.WriteString(u.userinfo)
+			sb.WriteByte('@')
+		}
+		sb.WriteString(u.host)
+		if u.hasPort {
+			sb.WriteByte(':')
+			sb.WriteString(u.port)
+		}
+	}
+	sb.WriteString(u.path)
+	if u.hasQuery {
+		sb.WriteByte('?')
+		sb.WriteString(u.query)
+	}
+	if u.hasFragment {
+		sb.WriteByte('#')
+		sb.WriteString(u.fragment)
+	}
+
+	return sb.String()
+}